@@ -0,0 +1,36 @@
+// Comando rebuild-daily-summaries refaz os totais diários a partir dos itens de
+// refeição, para preencher a tabela ou corrigir divergências.
+// Uso: go run ./cmd/rebuild-daily-summaries [-user 12] [-from 2025-01-01] [-to 2025-01-31]
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/juliapinheiro42/LightApp/database"
+	"github.com/juliapinheiro42/LightApp/internal/models"
+)
+
+func main() {
+	userID := flag.Uint("user", 0, "apenas este usuário (padrão: todos com refeições)")
+	from := flag.String("from", "", "primeiro dia, AAAA-MM-DD (padrão: sem limite)")
+	to := flag.String("to", "", "último dia, AAAA-MM-DD (padrão: sem limite)")
+	flag.Parse()
+
+	database.ConnectDatabase()
+	if err := models.MigrateDailySummary(database.DB); err != nil {
+		log.Fatalf("Erro ao migrar tabela de totais diários: %v", err)
+	}
+
+	var userIDs []uint
+	if *userID != 0 {
+		userIDs = []uint{*userID}
+	}
+
+	days, err := models.RebuildDailySummaries(database.DB, userIDs, *from, *to)
+	if err != nil {
+		log.Fatalf("Erro ao refazer totais diários: %v", err)
+	}
+
+	log.Printf("%d dias de totais refeitos", days)
+}
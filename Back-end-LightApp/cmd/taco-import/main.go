@@ -0,0 +1,42 @@
+// Comando taco-import carrega (ou atualiza) o catálogo de alimentos a partir
+// da planilha da TACO. Uso: go run ./cmd/taco-import -file Taco-4a-Edicao.csv
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/juliapinheiro42/LightApp/database"
+	"github.com/juliapinheiro42/LightApp/internal/models"
+	"github.com/juliapinheiro42/LightApp/internal/taco"
+	"gorm.io/gorm/clause"
+)
+
+func main() {
+	path := flag.String("file", "Taco-4a-Edicao.csv", "caminho do CSV da TACO")
+	flag.Parse()
+
+	file, err := os.Open(*path)
+	if err != nil {
+		log.Fatalf("Erro ao abrir o arquivo da TACO: %v", err)
+	}
+	defer file.Close()
+
+	foods, err := taco.Parse(file)
+	if err != nil {
+		log.Fatalf("Erro ao ler o arquivo da TACO: %v", err)
+	}
+
+	database.ConnectDatabase()
+	if err := models.MigrateFood(database.DB); err != nil {
+		log.Fatalf("Erro ao migrar tabela de alimentos: %v", err)
+	}
+
+	// Alimentos já existentes são atualizados; os snapshots dos itens de refeição não mudam
+	if err := database.DB.Clauses(clause.OnConflict{UpdateAll: true}).CreateInBatches(foods, 100).Error; err != nil {
+		log.Fatalf("Erro ao gravar alimentos: %v", err)
+	}
+
+	log.Printf("%d alimentos da TACO importados", len(foods))
+}
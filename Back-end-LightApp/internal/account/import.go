@@ -0,0 +1,311 @@
+package account
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juliapinheiro42/LightApp/internal/models"
+	"gorm.io/gorm"
+)
+
+// ExportFormatVersion identifica o formato do ZIP de dados pessoais. A
+// importação recusa versões mais novas que a do servidor.
+const ExportFormatVersion = 1
+
+// Tamanho máximo de cada JSON lido do ZIP
+const maxImportFileSize = 64 << 20
+
+// Manifest descreve o ZIP de dados pessoais (manifest.json)
+type Manifest struct {
+	FormatVersion int       `json:"format_version"`
+	UserID        uint      `json:"user_id"`
+	ExportedAt    time.Time `json:"exported_at"`
+	Files         []string  `json:"files"`
+}
+
+// ExportedFood identifica um alimento citado nos itens (foods.json)
+type ExportedFood struct {
+	ID       uint   `json:"id"`
+	Name     string `json:"name"`
+	Category string `json:"category"`
+}
+
+// Export é o conteúdo do ZIP usado na importação: o diário e os alimentos
+type Export struct {
+	Manifest Manifest
+	Meals    []models.Meal
+	Items    []models.MealItem
+	Foods    []ExportedFood
+}
+
+// ReadExport lê o diário de um ZIP gerado pela exportação de dados pessoais
+func ReadExport(archive *zip.Reader) (Export, error) {
+	var export Export
+	if err := readJSON(archive, "manifest.json", &export.Manifest, true); err != nil {
+		return export, err
+	}
+	if export.Manifest.FormatVersion < 1 || export.Manifest.FormatVersion > ExportFormatVersion {
+		return export, fmt.Errorf("Versão do arquivo não suportada: %d", export.Manifest.FormatVersion)
+	}
+	if err := readJSON(archive, "meals.json", &export.Meals, true); err != nil {
+		return export, err
+	}
+	if err := readJSON(archive, "meal_items.json", &export.Items, true); err != nil {
+		return export, err
+	}
+	if err := readJSON(archive, "foods.json", &export.Foods, false); err != nil {
+		return export, err
+	}
+	return export, nil
+}
+
+func readJSON(archive *zip.Reader, name string, target interface{}, required bool) error {
+	file, err := archive.Open(name)
+	if errors.Is(err, fs.ErrNotExist) && !required {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Arquivo %s não encontrado no ZIP", name)
+	}
+	defer file.Close()
+
+	if err := json.NewDecoder(io.LimitReader(file, maxImportFileSize)).Decode(target); err != nil {
+		return fmt.Errorf("Arquivo %s inválido: %v", name, err)
+	}
+	return nil
+}
+
+// Motivos de conflito informados no resultado da importação
+const (
+	ConflictMealDiffers   = "meal_differs"   // Já existe outra refeição no mesmo horário
+	ConflictFoodUnmatched = "food_unmatched" // Alimento não encontrado; item importado como registro rápido
+	ConflictFoodRemapped  = "food_remapped"  // Mesmo nome com outro ID; item associado ao alimento local
+)
+
+// ImportConflict descreve uma refeição ou item que não foi importado como está
+type ImportConflict struct {
+	Reason    string    `json:"reason"`
+	MealID    uint      `json:"meal_id"` // ID no arquivo importado
+	CreatedAt time.Time `json:"created_at"`
+	FoodID    *uint     `json:"food_id,omitempty"` // ID no arquivo importado
+	Food      string    `json:"food,omitempty"`
+	LocalID   *uint     `json:"local_food_id,omitempty"`
+}
+
+// ImportResult resume a importação
+type ImportResult struct {
+	MealsImported int              `json:"meals_imported"`
+	ItemsImported int              `json:"items_imported"`
+	MealsSkipped  int              `json:"meals_skipped"` // Já presentes, iguais ao arquivo
+	Conflicts     []ImportConflict `json:"conflicts"`
+}
+
+// Import grava no usuário as refeições do arquivo que ainda não existem. Uma
+// refeição é identificada pelo horário: se já houver uma no mesmo instante com
+// os mesmos itens, é ignorada, o que torna a importação repetível; com itens
+// diferentes, é informada como conflito e mantida como está. Os alimentos são
+// associados pelo número da TACO quando o nome confere, senão pelo nome.
+// Os nutrientes gravados nos itens de origem são preservados.
+func Import(db *gorm.DB, userID uint, export Export) (ImportResult, error) {
+	result := ImportResult{Conflicts: []ImportConflict{}}
+	if len(export.Meals) == 0 {
+		return result, nil
+	}
+
+	itemsByMeal := map[uint][]models.MealItem{}
+	for _, item := range export.Items {
+		itemsByMeal[item.MealID] = append(itemsByMeal[item.MealID], item)
+	}
+
+	foods, err := matchFoods(db, export)
+	if err != nil {
+		return result, err
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		existing, err := existingMeals(tx, userID, export.Meals)
+		if err != nil {
+			return err
+		}
+
+		for _, source := range export.Meals {
+			createdAt := source.CreatedAt.Truncate(time.Microsecond)
+			items := make([]models.MealItem, 0, len(itemsByMeal[source.ID]))
+			var itemConflicts []ImportConflict
+			for _, sourceItem := range itemsByMeal[source.ID] {
+				item, conflict := foods.resolve(sourceItem)
+				if conflict != nil {
+					conflict.MealID, conflict.CreatedAt = source.ID, createdAt
+					itemConflicts = append(itemConflicts, *conflict)
+				}
+				items = append(items, item)
+			}
+
+			if current, ok := existing[createdAt.UnixMicro()]; ok {
+				if itemsSignature(current.Items) == itemsSignature(items) {
+					result.MealsSkipped++
+				} else {
+					result.Conflicts = append(result.Conflicts, ImportConflict{
+						Reason: ConflictMealDiffers, MealID: source.ID, CreatedAt: createdAt,
+					})
+				}
+				continue
+			}
+
+			meal := models.Meal{
+				UserID:      userID,
+				MealType:    source.MealType,
+				CreatedAt:   createdAt,
+				Items:       items,
+				Note:        source.Note,
+				Hunger:      source.Hunger,
+				Fullness:    source.Fullness,
+				ContextTags: source.ContextTags,
+				Mood:        source.Mood,
+			}
+			if !models.IsValidMealType(meal.MealType) {
+				meal.MealType = ""
+			}
+			if err := tx.Create(&meal).Error; err != nil {
+				return err
+			}
+			existing[createdAt.UnixMicro()] = meal
+			result.Conflicts = append(result.Conflicts, itemConflicts...)
+			result.MealsImported++
+			result.ItemsImported += len(items)
+		}
+
+		if result.MealsImported == 0 {
+			return nil
+		}
+		_, err = models.RebuildDailySummaries(tx, []uint{userID}, "", "")
+		return err
+	})
+	return result, err
+}
+
+// existingMeals busca, com os itens, as refeições do usuário no período do arquivo
+func existingMeals(db *gorm.DB, userID uint, meals []models.Meal) (map[int64]models.Meal, error) {
+	first, last := meals[0].CreatedAt, meals[0].CreatedAt
+	for _, meal := range meals {
+		if meal.CreatedAt.Before(first) {
+			first = meal.CreatedAt
+		}
+		if meal.CreatedAt.After(last) {
+			last = meal.CreatedAt
+		}
+	}
+
+	var current []models.Meal
+	err := db.Preload("Items").
+		Where("user_id = ? AND created_at >= ? AND created_at <= ?", userID, first.Add(-time.Second), last.Add(time.Second)).
+		Find(&current).Error
+	if err != nil {
+		return nil, err
+	}
+	byTime := make(map[int64]models.Meal, len(current))
+	for _, meal := range current {
+		byTime[meal.CreatedAt.Truncate(time.Microsecond).UnixMicro()] = meal
+	}
+	return byTime, nil
+}
+
+// itemsSignature compara itens independentemente de IDs e da ordem
+func itemsSignature(items []models.MealItem) string {
+	parts := make([]string, len(items))
+	for i, item := range items {
+		food := "-"
+		if item.FoodID != nil {
+			food = fmt.Sprint(*item.FoodID)
+		}
+		parts[i] = fmt.Sprintf("%s|%s|%.2f|%.2f", food, item.Label, item.Amount, math.Round(item.Calories*100)/100)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ";")
+}
+
+// foodMatcher associa os alimentos do arquivo aos do catálogo local
+type foodMatcher struct {
+	source map[uint]ExportedFood // Alimentos do arquivo, por ID de origem
+	byID   map[uint]string       // Nome local por ID
+	byName map[string]uint       // ID local por nome normalizado
+}
+
+func matchFoods(db *gorm.DB, export Export) (*foodMatcher, error) {
+	matcher := &foodMatcher{source: map[uint]ExportedFood{}, byID: map[uint]string{}, byName: map[string]uint{}}
+
+	ids := []uint{}
+	names := []string{}
+	for _, food := range export.Foods {
+		matcher.source[food.ID] = food
+		names = append(names, normalizeFoodName(food.Name))
+	}
+	for _, item := range export.Items {
+		if item.FoodID != nil {
+			ids = append(ids, *item.FoodID)
+		}
+	}
+	if len(ids) == 0 {
+		return matcher, nil
+	}
+
+	var local []ExportedFood
+	err := db.Model(&models.Food{}).Select("id, name, category").
+		Where("id IN ? OR LOWER(TRIM(name)) IN ?", ids, append(names, "")).
+		Scan(&local).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, food := range local {
+		matcher.byID[food.ID] = food.Name
+		matcher.byName[normalizeFoodName(food.Name)] = food.ID
+	}
+	return matcher, nil
+}
+
+// resolve prepara o item para o usuário local. Sem o alimento, o item vira um
+// registro rápido com os nutrientes de origem e o nome como descrição.
+func (m *foodMatcher) resolve(source models.MealItem) (models.MealItem, *ImportConflict) {
+	item := source
+	item.ID, item.MealID = 0, 0
+	if item.FoodID == nil {
+		return item, nil
+	}
+
+	sourceID := *item.FoodID
+	sourceFood, described := m.source[sourceID]
+	localName, exists := m.byID[sourceID]
+
+	// Mesmo ID: vale se o arquivo não traz o nome ou se o nome confere
+	if exists && (!described || normalizeFoodName(localName) == normalizeFoodName(sourceFood.Name)) {
+		return item, nil
+	}
+
+	conflict := &ImportConflict{FoodID: &sourceID, Food: sourceFood.Name}
+	if localID, ok := m.byName[normalizeFoodName(sourceFood.Name)]; described && ok {
+		item.FoodID = &localID
+		conflict.Reason, conflict.LocalID = ConflictFoodRemapped, &localID
+		return item, conflict
+	}
+
+	item.FoodID = nil
+	item.QuickAdd = true
+	item.Label = sourceFood.Name
+	if item.Label == "" {
+		item.Label = fmt.Sprintf("Alimento %d", sourceID)
+	}
+	conflict.Reason = ConflictFoodUnmatched
+	return item, conflict
+}
+
+func normalizeFoodName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
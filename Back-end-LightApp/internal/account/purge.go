@@ -0,0 +1,89 @@
+// Package account cuida da remoção definitiva das contas com exclusão pedida
+package account
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/juliapinheiro42/LightApp/internal/models"
+	"github.com/juliapinheiro42/LightApp/internal/storage"
+	"github.com/juliapinheiro42/LightApp/internal/utils"
+	"gorm.io/gorm"
+)
+
+// Intervalo entre as verificações de contas a remover
+const purgeInterval = time.Hour
+
+// Purge apaga os dados do usuário no banco e as fotos no armazenamento
+func Purge(ctx context.Context, db *gorm.DB, store storage.Storage, userID uint) error {
+	if err := purgeLegacyRevokedTokens(db, userID); err != nil {
+		return err
+	}
+
+	keys, err := models.PurgeUserData(db, userID)
+	if err != nil {
+		return err
+	}
+
+	// O banco já não referencia as fotos; uma falha aqui deixa só arquivos órfãos
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		if err := store.Delete(ctx, key); err != nil {
+			log.Printf("Erro ao remover arquivo %s do usuário %d: %v", key, userID, err)
+		}
+	}
+	return nil
+}
+
+// purgeLegacyRevokedTokens apaga os tokens revogados antes da coluna user_id,
+// identificando o usuário pelo conteúdo do token
+func purgeLegacyRevokedTokens(db *gorm.DB, userID uint) error {
+	var tokens []models.RevokedToken
+	if err := db.Unscoped().Where("user_id = 0").Find(&tokens).Error; err != nil {
+		return err
+	}
+	ids := []uint{}
+	for _, token := range tokens {
+		if utils.TokenUserID(token.Token) == userID {
+			ids = append(ids, token.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return db.Unscoped().Delete(&models.RevokedToken{}, ids).Error
+}
+
+// PurgeDue remove as contas cujo prazo de exclusão terminou
+func PurgeDue(ctx context.Context, db *gorm.DB, store storage.Storage, now time.Time) (int, error) {
+	userIDs, err := models.AccountsDueForPurge(db, now)
+	if err != nil {
+		return 0, err
+	}
+	purged := 0
+	for _, userID := range userIDs {
+		if err := Purge(ctx, db, store, userID); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// StartPurger verifica periodicamente as contas a remover, em segundo plano
+func StartPurger(db *gorm.DB, store storage.Storage) {
+	go func() {
+		for {
+			purged, err := PurgeDue(context.Background(), db, store, time.Now())
+			if err != nil {
+				log.Printf("Erro ao remover contas excluídas: %v", err)
+			} else if purged > 0 {
+				log.Printf("%d contas excluídas removidas definitivamente", purged)
+			}
+			time.Sleep(purgeInterval)
+		}
+	}()
+}
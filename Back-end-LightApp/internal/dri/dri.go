@@ -0,0 +1,165 @@
+// Package dri compara a ingestão de micronutrientes com as Ingestões Dietéticas
+// de Referência (DRI). A tabela de referência é um arquivo versionado em data/;
+// uma nova revisão entra como um novo arquivo, e CurrentFile aponta para ela.
+package dri
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+//go:embed data/*.json
+var files embed.FS
+
+// CurrentFile é a revisão da tabela usada nos relatórios
+const CurrentFile = "data/dri-v1.json"
+
+// Situações da ingestão média frente às referências
+const (
+	StatusLikelyInadequate   = "likely_inadequate"   // Abaixo da EAR: provável deficiência
+	StatusPossiblyInadequate = "possibly_inadequate" // Entre a EAR e a RDA
+	StatusAdequate           = "adequate"            // Na RDA/AI ou acima, sem passar do UL
+	StatusBelowAI            = "below_ai"            // Abaixo da AI: adequação não pode ser afirmada
+	StatusAboveUL            = "above_ul"            // Acima do limite superior tolerável
+)
+
+// Reference são os valores de uma faixa de idade e sexo. Nem todo nutriente
+// tem EAR/RDA; nesses casos há apenas AI. Valores ausentes ficam nulos.
+type Reference struct {
+	Sex    string   `json:"sex"`
+	AgeMin int      `json:"age_min"`
+	AgeMax int      `json:"age_max"`
+	EAR    *float64 `json:"ear,omitempty"`
+	RDA    *float64 `json:"rda,omitempty"`
+	AI     *float64 `json:"ai,omitempty"`
+	UL     *float64 `json:"ul,omitempty"`
+}
+
+type Nutrient struct {
+	Label      string      `json:"label"`
+	Unit       string      `json:"unit"`
+	Note       string      `json:"note,omitempty"`
+	References []Reference `json:"references"`
+}
+
+// Table é um arquivo de referência completo
+type Table struct {
+	Version   string              `json:"version"`
+	Source    string              `json:"source"`
+	Nutrients map[string]Nutrient `json:"nutrients"`
+}
+
+// Load lê a revisão atual da tabela de referência
+func Load() (Table, error) {
+	data, err := files.ReadFile(CurrentFile)
+	if err != nil {
+		return Table{}, err
+	}
+	var table Table
+	if err := json.Unmarshal(data, &table); err != nil {
+		return Table{}, fmt.Errorf("tabela de DRI inválida: %w", err)
+	}
+	return table, nil
+}
+
+// ErrNoReference indica que a tabela não cobre a idade ou o sexo informados
+var ErrNoReference = errors.New("Não há valores de referência para a idade e o sexo do perfil")
+
+// Assessment é a comparação de um nutriente
+type Assessment struct {
+	Nutrient      string   `json:"nutrient"`
+	Label         string   `json:"label"`
+	Unit          string   `json:"unit"`
+	Intake        float64  `json:"intake"` // Média diária no período
+	EAR           *float64 `json:"ear,omitempty"`
+	RDA           *float64 `json:"rda,omitempty"`
+	AI            *float64 `json:"ai,omitempty"`
+	UL            *float64 `json:"ul,omitempty"`
+	PercentOfGoal float64  `json:"percent_of_goal"` // Percentual da RDA (ou AI)
+	Status        string   `json:"status"`
+	Note          string   `json:"note,omitempty"`
+}
+
+// Assess compara a ingestão média de cada nutriente com a faixa do perfil.
+// sex segue models.User.Sex: "male" ou "female".
+// O resultado vem ordenado com os alertas primeiro.
+func (t Table) Assess(intake map[string]float64, age int, sex string) ([]Assessment, error) {
+	if sex != "male" {
+		sex = "female"
+	}
+
+	var assessments []Assessment
+	for key, nutrient := range t.Nutrients {
+		reference, ok := nutrient.referenceFor(age, sex)
+		if !ok {
+			continue
+		}
+		value := intake[key]
+		assessment := Assessment{
+			Nutrient: key,
+			Label:    nutrient.Label,
+			Unit:     nutrient.Unit,
+			Intake:   math.Round(value*100) / 100,
+			EAR:      reference.EAR,
+			RDA:      reference.RDA,
+			AI:       reference.AI,
+			UL:       reference.UL,
+			Note:     nutrient.Note,
+		}
+
+		goal := reference.RDA
+		if goal == nil {
+			goal = reference.AI
+		}
+		if goal != nil && *goal > 0 {
+			assessment.PercentOfGoal = math.Round(value / *goal * 1000) / 10
+		}
+
+		switch {
+		case reference.UL != nil && value > *reference.UL:
+			assessment.Status = StatusAboveUL
+		case reference.EAR != nil && value < *reference.EAR:
+			assessment.Status = StatusLikelyInadequate
+		case reference.RDA != nil && value < *reference.RDA:
+			assessment.Status = StatusPossiblyInadequate
+		case reference.RDA == nil && reference.AI != nil && value < *reference.AI:
+			assessment.Status = StatusBelowAI
+		default:
+			assessment.Status = StatusAdequate
+		}
+		assessments = append(assessments, assessment)
+	}
+	if len(assessments) == 0 {
+		return nil, ErrNoReference
+	}
+
+	sort.Slice(assessments, func(i, j int) bool {
+		if statusOrder[assessments[i].Status] != statusOrder[assessments[j].Status] {
+			return statusOrder[assessments[i].Status] < statusOrder[assessments[j].Status]
+		}
+		return assessments[i].Label < assessments[j].Label
+	})
+	return assessments, nil
+}
+
+// Ordem de exibição: deficiências e excessos primeiro
+var statusOrder = map[string]int{
+	StatusLikelyInadequate:   0,
+	StatusAboveUL:            1,
+	StatusPossiblyInadequate: 2,
+	StatusBelowAI:            3,
+	StatusAdequate:           4,
+}
+
+func (n Nutrient) referenceFor(age int, sex string) (Reference, bool) {
+	for _, reference := range n.References {
+		if reference.Sex == sex && age >= reference.AgeMin && age <= reference.AgeMax {
+			return reference, true
+		}
+	}
+	return Reference{}, false
+}
@@ -0,0 +1,92 @@
+package export
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"errors"
+	"io"
+)
+
+// Quantidade de linhas entre cada envio ao destino
+const flushEvery = 500
+
+type csvWriter struct {
+	dest    io.Writer
+	archive *zip.Writer // Apenas com várias planilhas
+	csv     *csv.Writer
+	rows    int
+}
+
+func newCSV(w io.Writer, multiple bool) *csvWriter {
+	writer := &csvWriter{dest: w}
+	if multiple {
+		writer.archive = zip.NewWriter(w)
+	}
+	return writer
+}
+
+func (w *csvWriter) Sheet(name string, header []string) error {
+	if w.csv != nil {
+		if w.archive == nil {
+			return errors.New("o CSV aceita apenas uma planilha")
+		}
+		if err := w.flush(); err != nil {
+			return err
+		}
+	}
+
+	out := w.dest
+	if w.archive != nil {
+		file, err := w.archive.Create(name + ".csv")
+		if err != nil {
+			return err
+		}
+		out = file
+	}
+	w.csv = csv.NewWriter(out)
+	return w.csv.Write(header)
+}
+
+func (w *csvWriter) WriteRow(values []any) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		record[i], _ = text(value)
+	}
+	if err := w.csv.Write(record); err != nil {
+		return err
+	}
+	w.rows++
+	if w.rows%flushEvery == 0 {
+		return w.flush()
+	}
+	return nil
+}
+
+// flush envia as linhas pendentes e, se o destino permitir, a resposta HTTP
+func (w *csvWriter) flush() error {
+	w.csv.Flush()
+	if err := w.csv.Error(); err != nil {
+		return err
+	}
+	if w.archive != nil {
+		if err := w.archive.Flush(); err != nil {
+			return err
+		}
+	}
+	if flusher, ok := w.dest.(interface{ Flush() }); ok {
+		flusher.Flush()
+	}
+	return nil
+}
+
+func (w *csvWriter) Close() error {
+	if w.csv != nil {
+		if err := w.flush(); err != nil {
+			return err
+		}
+	}
+	if w.archive != nil {
+		return w.archive.Close()
+	}
+	return nil
+}
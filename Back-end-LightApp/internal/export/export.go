@@ -0,0 +1,75 @@
+// Package export grava tabelas em CSV ou XLSX diretamente no destino, linha a
+// linha, para que períodos longos não precisem ficar em memória
+package export
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// Formatos aceitos
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// Writer grava uma ou mais planilhas em sequência. Os valores das linhas podem
+// ser string, float64, int, int64, uint ou bool.
+type Writer interface {
+	// Sheet inicia uma nova planilha com o cabeçalho informado
+	Sheet(name string, header []string) error
+	WriteRow(values []any) error
+	// Close finaliza o arquivo; não fecha o destino
+	Close() error
+}
+
+// New cria o Writer do formato. Com multiple, o CSV vira um ZIP com um arquivo
+// por planilha; o XLSX sempre aceita várias planilhas.
+func New(format string, w io.Writer, multiple bool) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSV(w, multiple), nil
+	case FormatXLSX:
+		return newXLSX(w), nil
+	}
+	return nil, fmt.Errorf("formato desconhecido: %s", format)
+}
+
+// ContentType retorna o tipo MIME e a extensão do arquivo gerado
+func ContentType(format string, multiple bool) (string, string) {
+	switch {
+	case format == FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", "xlsx"
+	case multiple:
+		return "application/zip", "zip"
+	}
+	return "text/csv; charset=utf-8", "csv"
+}
+
+// formatNumber arredonda para 3 casas, o suficiente para gramas e miligramas
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(math.Round(value*1000)/1000, 'f', -1, 64)
+}
+
+// text converte um valor para texto; numeric indica se é número
+func text(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, false
+	case float64:
+		return formatNumber(v), true
+	case int:
+		return strconv.Itoa(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case uint:
+		return strconv.FormatUint(uint64(v), 10), true
+	case bool:
+		return strconv.FormatBool(v), false
+	case nil:
+		return "", false
+	}
+	return fmt.Sprint(value), false
+}
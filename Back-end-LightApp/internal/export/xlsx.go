@@ -0,0 +1,155 @@
+package export
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// xlsxWriter gera um XLSX mínimo (SpreadsheetML) com textos inline, sem a
+// tabela de textos compartilhados, para poder gravar as linhas em sequência.
+// O workbook e os tipos de conteúdo são gravados no fechamento, quando todas
+// as planilhas já são conhecidas.
+type xlsxWriter struct {
+	dest    io.Writer
+	archive *zip.Writer
+	sheet   io.Writer
+	sheets  []string
+	row     int
+}
+
+func newXLSX(w io.Writer) *xlsxWriter {
+	return &xlsxWriter{dest: w, archive: zip.NewWriter(w)}
+}
+
+const sheetHeader = xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>` +
+	`<sheetData>`
+
+const sheetFooter = `</sheetData></worksheet>`
+
+func (w *xlsxWriter) Sheet(name string, header []string) error {
+	if err := w.endSheet(); err != nil {
+		return err
+	}
+
+	w.sheets = append(w.sheets, name)
+	file, err := w.archive.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", len(w.sheets)))
+	if err != nil {
+		return err
+	}
+	w.sheet = file
+	w.row = 0
+	if _, err := io.WriteString(w.sheet, sheetHeader); err != nil {
+		return err
+	}
+
+	values := make([]any, len(header))
+	for i, column := range header {
+		values[i] = column
+	}
+	return w.WriteRow(values)
+}
+
+func (w *xlsxWriter) WriteRow(values []any) error {
+	w.row++
+	var b strings.Builder
+	fmt.Fprintf(&b, `<row r="%d">`, w.row)
+	for i, value := range values {
+		content, numeric := text(value)
+		if content == "" {
+			continue
+		}
+		ref := columnName(i) + strconv.Itoa(w.row)
+		if numeric {
+			fmt.Fprintf(&b, `<c r="%s"><v>%s</v></c>`, ref, content)
+			continue
+		}
+		fmt.Fprintf(&b, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">`, ref)
+		xml.EscapeText(&b, []byte(content))
+		b.WriteString(`</t></is></c>`)
+	}
+	b.WriteString(`</row>`)
+	if _, err := io.WriteString(w.sheet, b.String()); err != nil {
+		return err
+	}
+
+	if w.row%flushEvery == 0 {
+		if err := w.archive.Flush(); err != nil {
+			return err
+		}
+		if flusher, ok := w.dest.(interface{ Flush() }); ok {
+			flusher.Flush()
+		}
+	}
+	return nil
+}
+
+func (w *xlsxWriter) endSheet() error {
+	if w.sheet == nil {
+		return nil
+	}
+	_, err := io.WriteString(w.sheet, sheetFooter)
+	w.sheet = nil
+	return err
+}
+
+func (w *xlsxWriter) Close() error {
+	if err := w.endSheet(); err != nil {
+		return err
+	}
+
+	var types, workbook, relations strings.Builder
+	for i, name := range w.sheets {
+		fmt.Fprintf(&types, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+		fmt.Fprintf(&workbook, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escape(name), i+1, i+1)
+		fmt.Fprintf(&relations, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+	}
+
+	files := []struct{ name, content string }{
+		{"[Content_Types].xml", xml.Header +
+			`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			types.String() + `</Types>`},
+		{"_rels/.rels", xml.Header +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", xml.Header +
+			`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<bookViews><workbookView/></bookViews><sheets>` + workbook.String() + `</sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", xml.Header +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			relations.String() + `</Relationships>`},
+	}
+	for _, file := range files {
+		out, err := w.archive.Create(file.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(out, file.content); err != nil {
+			return err
+		}
+	}
+	return w.archive.Close()
+}
+
+// columnName converte o índice da coluna (a partir de 0) em letras: A, B, ..., AA
+func columnName(index int) string {
+	name := ""
+	for index++; index > 0; index = (index - 1) / 26 {
+		name = string(rune('A'+(index-1)%26)) + name
+	}
+	return name
+}
+
+func escape(value string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(value))
+	return b.String()
+}
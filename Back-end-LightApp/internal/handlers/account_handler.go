@@ -0,0 +1,267 @@
+package handlers
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juliapinheiro42/LightApp/config"
+	"github.com/juliapinheiro42/LightApp/database"
+	"github.com/juliapinheiro42/LightApp/internal/account"
+	"github.com/juliapinheiro42/LightApp/internal/models"
+	"github.com/juliapinheiro42/LightApp/internal/storage"
+	"github.com/juliapinheiro42/LightApp/internal/utils"
+)
+
+// ExportMyData gera um ZIP com todos os dados pessoais do usuário em JSON
+// (LGPD, art. 18), incluindo os arquivos originais das fotos das refeições
+func ExportMyData(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+		return
+	}
+	user.Password = ""
+
+	var meals []models.Meal
+	var items []models.MealItem
+	var photos []models.MealPhoto
+	var plans []models.MealPlan
+	var lists []models.ShoppingList
+	var recipes []models.Recipe
+	var fasts []models.FastingSession
+	var hydration []models.HydrationEntry
+	var foods []account.ExportedFood
+	var weights []models.WeightEntry
+	var measurements []models.BodyMeasurement
+
+	mealIDs := database.DB.Model(&models.Meal{}).Select("id").Where("user_id = ?", user.ID)
+	queries := []error{
+		database.DB.Where("user_id = ?", user.ID).Order("created_at").Find(&meals).Error,
+		database.DB.Where("meal_id IN (?)", mealIDs).Order("meal_id, id").Find(&items).Error,
+		database.DB.Where("user_id = ?", user.ID).Order("created_at").Find(&photos).Error,
+		database.DB.Preload("Meals.Items").Where("user_id = ?", user.ID).Order("created_at").Find(&plans).Error,
+		database.DB.Preload("Items").Where("user_id = ?", user.ID).Order("created_at").Find(&lists).Error,
+		database.DB.Preload("Items").Where("user_id = ?", user.ID).Order("created_at").Find(&recipes).Error,
+		database.DB.Where("user_id = ?", user.ID).Order("started_at").Find(&fasts).Error,
+		database.DB.Where("user_id = ?", user.ID).Order("consumed_at").Find(&hydration).Error,
+		database.DB.Where("user_id = ?", user.ID).Order("measured_at").Find(&weights).Error,
+		database.DB.Where("user_id = ?", user.ID).Order("measured_at").Find(&measurements).Error,
+		// Alimentos citados nos itens, para a importação em outro ambiente
+		database.DB.Model(&models.Food{}).Select("id, name, category").
+			Where("id IN (?)", database.DB.Model(&models.MealItem{}).Select("food_id").Where("meal_id IN (?)", mealIDs)).
+			Order("id").Scan(&foods).Error,
+	}
+	for _, err := range queries {
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar dados do usuário"})
+			return
+		}
+	}
+
+	bodyMetrics := gin.H{
+		"weight":          user.Weight,
+		"height":          user.Height,
+		"age":             user.Age,
+		"gender":          user.Gender,
+		"sex":             user.Sex,
+		"activity":        user.Activity,
+		"activity_level":  user.ActivityLevel,
+		"energy_equation": user.EnergyEquation,
+		"updated_at":      user.UpdatedAt,
+		"weight_log":      weights,
+		"measurements":    measurements,
+	}
+
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", user},
+		{"body_metrics.json", bodyMetrics},
+		{"meals.json", meals},
+		{"meal_items.json", items},
+		{"foods.json", foods},
+		{"photos.json", photos},
+		{"fasting_sessions.json", fasts},
+		{"hydration.json", hydration},
+		{"meal_plans.json", plans},
+		{"shopping_lists.json", lists},
+		{"recipes.json", recipes},
+	}
+
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", `attachment; filename="lightapp-dados-`+time.Now().Format(models.DateLayout)+`.zip"`)
+	c.Status(http.StatusOK)
+
+	archive := zip.NewWriter(c.Writer)
+	names := []string{}
+	for _, file := range files {
+		if err := writeJSONFile(archive, file.name, file.data); err != nil {
+			abortExport(c, err)
+			return
+		}
+		names = append(names, file.name)
+	}
+
+	// Arquivos originais das fotos, em photos/
+	for _, photo := range photos {
+		name := "photos/" + path.Base(photo.Key)
+		err := copyStoredFile(c, archive, name, photo.Key)
+		if errors.Is(err, storage.ErrNotFound) {
+			continue // Arquivo já removido do armazenamento; os metadados estão em photos.json
+		}
+		if err != nil {
+			abortExport(c, err)
+			return
+		}
+		names = append(names, name)
+	}
+
+	manifest := account.Manifest{
+		FormatVersion: account.ExportFormatVersion,
+		UserID:        user.ID,
+		ExportedAt:    time.Now(),
+		Files:         names,
+	}
+	if err := writeJSONFile(archive, "manifest.json", manifest); err != nil {
+		abortExport(c, err)
+		return
+	}
+	if err := archive.Close(); err != nil {
+		abortExport(c, err)
+	}
+}
+
+func writeJSONFile(archive *zip.Writer, name string, data interface{}) error {
+	file, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
+
+func copyStoredFile(c *gin.Context, archive *zip.Writer, name, key string) error {
+	body, err := storage.Default.Get(c.Request.Context(), key)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	file, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(file, body)
+	return err
+}
+
+// DeleteAccount agenda a exclusão da conta. Exige a senha atual; os dados são
+// apagados definitivamente depois de models.AccountDeletionGrace, e entrar de
+// novo nesse prazo cancela o pedido.
+func DeleteAccount(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	var request struct {
+		Password     string `json:"password" binding:"required"`
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Informe a senha para excluir a conta"})
+		return
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+		return
+	}
+	if !utils.CheckPasswordHash(request.Password, user.Password) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Senha incorreta"})
+		return
+	}
+
+	purgeAt := time.Now().Add(models.AccountDeletionGrace)
+	if err := database.DB.Model(&user).Update("deletion_scheduled_at", purgeAt).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao agendar exclusão da conta"})
+		return
+	}
+
+	if request.RefreshToken != "" && !utils.IsTokenRevoked(request.RefreshToken) {
+		_ = utils.RevokeToken(request.RefreshToken)
+	}
+	c.SetCookie("access_token", "", -1, "/", config.CookieDomain, config.SecureCookie, true)
+	c.SetCookie("refresh_token", "", -1, "/", config.CookieDomain, config.SecureCookie, true)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Exclusão da conta agendada. Entre novamente antes da data para cancelar.",
+		"purge_at": purgeAt,
+	})
+}
+
+// Tamanho máximo do ZIP aceito na importação
+const maxImportSize = 512 << 20
+
+// ImportMyData restaura o diário a partir do ZIP gerado por ExportMyData,
+// inclusive de outro ambiente. Pode ser repetida: refeições já importadas são
+// ignoradas, e as divergências voltam em conflicts.
+func ImportMyData(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Envie o arquivo ZIP no campo file"})
+		return
+	}
+	if header.Size > maxImportSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Arquivo muito grande"})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Falha ao ler o arquivo"})
+		return
+	}
+	defer file.Close()
+
+	archive, err := zip.NewReader(file, header.Size)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "O arquivo não é um ZIP válido"})
+		return
+	}
+	data, err := account.ReadExport(archive)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := account.Import(database.DB, userID.(uint), data)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao importar dados"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juliapinheiro42/LightApp/database"
+	"github.com/juliapinheiro42/LightApp/internal/models"
+	"gorm.io/gorm"
+)
+
+// RecalculateMealItems regrava os nutrientes dos itens de refeição a partir do
+// catálogo atual. Os resumos usam o snapshot gravado no registro, então esta é
+// a única forma de uma correção no catálogo alterar o histórico.
+func RecalculateMealItems(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	var request struct {
+		FoodID uint   `json:"food_id"`
+		UserID uint   `json:"user_id"`
+		Reason string `json:"reason" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Informe o motivo do recálculo"})
+		return
+	}
+
+	filter := models.MealItemRecalcFilter{FoodID: request.FoodID, UserID: request.UserID}
+
+	var updated int64
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		userIDs, err := filter.AffectedUsers(tx)
+		if err != nil {
+			return err
+		}
+		updated, err = models.RecalculateMealItemNutrients(tx, filter)
+		if err != nil {
+			return err
+		}
+		if _, err := models.RebuildDailySummaries(tx, userIDs, "", ""); err != nil {
+			return err
+		}
+
+		return models.RecordAudit(tx, adminID.(uint), "recalculate_meal_items", gin.H{
+			"food_id":       request.FoodID,
+			"user_id":       request.UserID,
+			"reason":        request.Reason,
+			"items_updated": updated,
+		})
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao recalcular itens de refeição"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Itens recalculados a partir do catálogo atual",
+		"items_updated": updated,
+	})
+}
@@ -3,12 +3,14 @@ package handlers
 import (
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/juliapinheiro42/LightApp/config"
 	"github.com/juliapinheiro42/LightApp/database"
 	"github.com/juliapinheiro42/LightApp/internal/models"
 	"github.com/juliapinheiro42/LightApp/internal/utils"
+	"gorm.io/gorm"
 )
 
 // 🚀 Registro de Usuário
@@ -18,6 +20,16 @@ func Register(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if err := user.NormalizeEnergyProfile(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if user.Weight != 0 {
+		if err := models.ValidateWeight(user.Weight); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
 
 	// Verifica se o e-mail já está cadastrado
 	var existingUser models.User
@@ -34,7 +46,17 @@ func Register(c *gin.Context) {
 	}
 	user.Password = hashedPassword
 
-	if err := database.DB.Create(&user).Error; err != nil {
+	// O peso do cadastro é a primeira pesagem do histórico
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		if user.Weight == 0 {
+			return nil
+		}
+		return tx.Create(&models.WeightEntry{UserID: user.ID, Weight: user.Weight, MeasuredAt: user.CreatedAt}).Error
+	})
+	if err != nil {
 		log.Printf("Erro ao criar usuário: %v", err) // Log do erro
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar usuário"})
 		return
@@ -65,6 +87,20 @@ func Login(c *gin.Context) {
 		return
 	}
 
+	// Entrar durante o prazo de exclusão cancela o pedido
+	deletionCancelled := false
+	if user.DeletionScheduledAt != nil {
+		if !time.Now().Before(*user.DeletionScheduledAt) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Conta excluída"})
+			return
+		}
+		if err := database.DB.Model(&user).Update("deletion_scheduled_at", nil).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao cancelar exclusão da conta"})
+			return
+		}
+		deletionCancelled = true
+	}
+
 	// Gera tokens
 	accessToken, err := utils.GenerateAccessToken(user.ID, user.Email) // Passe o user.ID
 	if err != nil {
@@ -83,10 +119,11 @@ func Login(c *gin.Context) {
 	c.SetCookie("refresh_token", refreshToken, config.RefreshTokenExpire, "/", config.CookieDomain, config.SecureCookie, true)
 
 	c.JSON(http.StatusOK, gin.H{
-		"message":       "Login realizado com sucesso!",
-		"access_token":  accessToken,
-		"refresh_token": refreshToken,
-		"user_id":       user.ID,
+		"message":            "Login realizado com sucesso!",
+		"access_token":       accessToken,
+		"refresh_token":      refreshToken,
+		"user_id":            user.ID,
+		"deletion_cancelled": deletionCancelled,
 	})
 }
 
@@ -120,6 +157,10 @@ func RefreshToken(c *gin.Context) {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não encontrado"})
 		return
 	}
+	if user.DeletionScheduledAt != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Conta com exclusão agendada. Entre novamente para cancelar."})
+		return
+	}
 
 	// Gera um novo access token com o user_id
 	newAccessToken, err := utils.GenerateAccessToken(user.ID, user.Email) // Passe o user.ID
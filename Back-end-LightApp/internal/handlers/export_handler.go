@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juliapinheiro42/LightApp/database"
+	"github.com/juliapinheiro42/LightApp/internal/export"
+	"github.com/juliapinheiro42/LightApp/internal/models"
+)
+
+// diaryDay acumula os totais de um dia durante a exportação
+type diaryDay struct {
+	date      string
+	meals     map[uint]bool
+	items     int
+	nutrients models.Nutrients
+}
+
+// ExportDiary exporta o diário alimentar do período (?from=&to=, padrão:
+// últimos 30 dias) em CSV ou XLSX (?format=), com uma linha por item de
+// refeição. Com ?totals=true, inclui uma planilha com os totais por dia (no
+// CSV, o arquivo vira um ZIP com os dois CSVs). O arquivo é gerado enquanto
+// os itens são lidos do banco.
+func ExportDiary(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	format := c.DefaultQuery("format", export.FormatCSV)
+	if format != export.FormatCSV && format != export.FormatXLSX {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Formato inválido (use csv ou xlsx)"})
+		return
+	}
+	withTotals := c.Query("totals") == "true"
+
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+		return
+	}
+	loc := user.Location()
+
+	from, to, err := parseDateRange(c, loc, 30)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	contentType, extension := export.ContentType(format, withTotals)
+	filename := fmt.Sprintf("diario-%s-%s.%s", from.Format(models.DateLayout), to.Format(models.DateLayout), extension)
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Status(http.StatusOK)
+
+	writer, _ := export.New(format, c.Writer, withTotals)
+	nutrientColumns := models.NutrientColumns()
+
+	header := append([]string{"date", "time", "meal_id", "meal_type", "food", "taco_id", "quick_add", "quantity", "unit", "amount_g"}, nutrientColumns...)
+	if err := writer.Sheet("diary", header); err != nil {
+		abortExport(c, err)
+		return
+	}
+
+	var days []*diaryDay
+	err = models.StreamDiary(database.DB, user.ID, from, to.AddDate(0, 0, 1), func(row models.DiaryRow) error {
+		at := row.CreatedAt.In(loc)
+		date := at.Format(models.DateLayout)
+		if len(days) == 0 || days[len(days)-1].date != date {
+			days = append(days, &diaryDay{date: date, meals: map[uint]bool{}})
+		}
+		day := days[len(days)-1]
+		day.meals[row.MealID] = true
+		day.items++
+		day.nutrients = day.nutrients.Add(row.Nutrients)
+
+		var tacoID any
+		if row.FoodID != nil {
+			tacoID = *row.FoodID
+		}
+		values := []any{date, at.Format("15:04"), row.MealID, row.MealType, row.Food, tacoID,
+			row.QuickAdd, row.Quantity, row.Unit, row.Amount}
+		for _, value := range row.Nutrients.Columns() {
+			values = append(values, value)
+		}
+		return writer.WriteRow(values)
+	})
+	if err != nil {
+		abortExport(c, err)
+		return
+	}
+
+	if withTotals {
+		header := append([]string{"date", "meals", "items"}, nutrientColumns...)
+		if err := writer.Sheet("daily_totals", header); err != nil {
+			abortExport(c, err)
+			return
+		}
+		for _, day := range days {
+			values := []any{day.date, len(day.meals), day.items}
+			for _, value := range day.nutrients.Columns() {
+				values = append(values, value)
+			}
+			if err := writer.WriteRow(values); err != nil {
+				abortExport(c, err)
+				return
+			}
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		abortExport(c, err)
+	}
+}
+
+// abortExport interrompe uma exportação já iniciada. Como o status não pode
+// mais mudar, a conexão é encerrada para o cliente não receber um arquivo
+// truncado como se estivesse completo.
+func abortExport(c *gin.Context, err error) {
+	log.Printf("Erro ao exportar dados: %v", err)
+	c.Abort()
+	if conn, _, err := c.Writer.Hijack(); err == nil {
+		conn.Close()
+	}
+}
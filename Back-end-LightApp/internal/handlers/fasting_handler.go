@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juliapinheiro42/LightApp/database"
+	"github.com/juliapinheiro42/LightApp/internal/models"
+)
+
+// Limites do histórico de jejum, em dias
+const (
+	defaultFastingHistoryDays = 30
+	maxFastingHistoryDays     = 365
+)
+
+func StartFast(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	var request struct {
+		TargetHours float64    `json:"target_hours"`
+		StartedAt   *time.Time `json:"started_at"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if request.TargetHours < 1 || request.TargetHours > 72 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Meta de jejum inválida (entre 1 e 72 horas)"})
+		return
+	}
+
+	active, err := models.ActiveFast(database.DB, userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar jejum em andamento"})
+		return
+	}
+	if active != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Já existe um jejum em andamento"})
+		return
+	}
+
+	session := models.FastingSession{
+		UserID:      userID.(uint),
+		StartedAt:   time.Now(),
+		TargetHours: request.TargetHours,
+	}
+	if request.StartedAt != nil {
+		if request.StartedAt.After(time.Now()) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "O início do jejum não pode estar no futuro"})
+			return
+		}
+		session.StartedAt = *request.StartedAt
+	}
+
+	if err := database.DB.Create(&session).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao iniciar jejum"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, session)
+}
+
+func EndFast(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	var request struct {
+		EndedAt *time.Time `json:"ended_at"`
+	}
+	// Corpo opcional: sem horário, o jejum termina agora
+	_ = c.ShouldBindJSON(&request)
+
+	session, err := models.ActiveFast(database.DB, userID.(uint))
+	if err != nil || session == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Nenhum jejum em andamento"})
+		return
+	}
+
+	endedAt := time.Now()
+	if request.EndedAt != nil {
+		endedAt = *request.EndedAt
+	}
+	if !endedAt.After(session.StartedAt) || endedAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Horário de término inválido"})
+		return
+	}
+
+	session.EndedAt = &endedAt
+	if err := database.DB.Save(session).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao encerrar jejum"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"session":   session,
+		"hours":     session.Duration().Hours(),
+		"completed": session.Completed(),
+	})
+}
+
+func GetCurrentFast(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	session, err := models.ActiveFast(database.DB, userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar jejum em andamento"})
+		return
+	}
+	if session == nil {
+		c.JSON(http.StatusOK, gin.H{"active": false})
+		return
+	}
+
+	elapsed := session.Duration().Hours()
+	c.JSON(http.StatusOK, gin.H{
+		"active":          true,
+		"session":         session,
+		"elapsed_hours":   elapsed,
+		"remaining_hours": max(session.TargetHours-elapsed, 0),
+		"target_reached":  elapsed >= session.TargetHours,
+	})
+}
+
+// GetFastingHistory retorna as sessões dos últimos ?days= dias com sequências
+// e médias calculadas no fuso horário do usuário
+func GetFastingHistory(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	days := defaultFastingHistoryDays
+	if value := c.Query("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 || parsed > maxFastingHistoryDays {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Número de dias inválido"})
+			return
+		}
+		days = parsed
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+		return
+	}
+	loc := user.Location()
+
+	now := time.Now().In(loc)
+	since := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, -days)
+
+	var sessions []models.FastingSession
+	if err := database.DB.Where("user_id = ? AND started_at >= ?", userID, since).
+		Order("started_at DESC").Find(&sessions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar histórico de jejum"})
+		return
+	}
+
+	var mealTimes []time.Time
+	if err := database.DB.Model(&models.Meal{}).
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Pluck("created_at", &mealTimes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar horários das refeições"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"time_zone": loc.String(),
+		"days":      days,
+		"stats":     models.BuildFastingStats(sessions, mealTimes, loc, now),
+		"sessions":  sessions,
+	})
+}
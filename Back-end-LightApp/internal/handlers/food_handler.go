@@ -2,10 +2,12 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/juliapinheiro42/LightApp/database"
 	"github.com/juliapinheiro42/LightApp/internal/models"
+	"gorm.io/gorm"
 )
 
 func GetFood(c *gin.Context) {
@@ -33,3 +35,57 @@ func GetFoodByID(c *gin.Context) {
 
 	c.JSON(http.StatusOK, food)
 }
+
+// Limite padrão e máximo de alimentos retornados no histórico
+const (
+	defaultFoodHistoryLimit = 10
+	maxFoodHistoryLimit     = 50
+)
+
+func GetRecentFoods(c *gin.Context) {
+	getFoodHistory(c, models.RecentFoods)
+}
+
+func GetFrequentFoods(c *gin.Context) {
+	getFoodHistory(c, models.FrequentFoods)
+}
+
+func getFoodHistory(c *gin.Context, fetch func(*gorm.DB, uint, models.FoodHistoryFilter) ([]models.FoodUsage, error)) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	filter := models.FoodHistoryFilter{
+		MealType:  c.Query("meal_type"),
+		TimeOfDay: c.Query("time_of_day"),
+		Limit:     defaultFoodHistoryLimit,
+	}
+
+	if !models.IsValidMealType(filter.MealType) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Tipo de refeição inválido"})
+		return
+	}
+	if !models.IsValidTimeOfDay(filter.TimeOfDay) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Período do dia inválido"})
+		return
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Limite inválido"})
+			return
+		}
+		filter.Limit = min(parsed, maxFoodHistoryLimit)
+	}
+
+	foods, err := fetch(database.DB, userID.(uint), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar histórico de alimentos"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"foods": foods})
+}
@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juliapinheiro42/LightApp/database"
+	"github.com/juliapinheiro42/LightApp/internal/models"
+)
+
+type hydrationRequest struct {
+	AmountML   float64    `json:"amount_ml"`
+	Beverage   string     `json:"beverage"`
+	ConsumedAt *time.Time `json:"consumed_at"`
+}
+
+func (r hydrationRequest) validate() string {
+	if r.AmountML <= 0 || r.AmountML > 5000 {
+		return "Quantidade de água inválida"
+	}
+	if !models.IsValidBeverage(r.Beverage) {
+		return "Tipo de bebida inválido"
+	}
+	return ""
+}
+
+func LogHydration(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	var request hydrationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if msg := request.validate(); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	entry := models.HydrationEntry{
+		UserID:     userID.(uint),
+		AmountML:   request.AmountML,
+		Beverage:   request.Beverage,
+		ConsumedAt: time.Now(),
+	}
+	if request.ConsumedAt != nil {
+		entry.ConsumedAt = *request.ConsumedAt
+	}
+
+	if err := database.DB.Create(&entry).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao registrar hidratação"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+func UpdateHydration(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	var entry models.HydrationEntry
+	if err := database.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&entry).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Registro de hidratação não encontrado"})
+		return
+	}
+
+	var request hydrationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if msg := request.validate(); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	entry.AmountML = request.AmountML
+	if request.Beverage != "" {
+		entry.Beverage = request.Beverage
+	}
+	if request.ConsumedAt != nil {
+		entry.ConsumedAt = *request.ConsumedAt
+	}
+
+	if err := database.DB.Save(&entry).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar hidratação"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+func DeleteHydration(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	result := database.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).Delete(&models.HydrationEntry{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao remover hidratação"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Registro de hidratação não encontrado"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Registro de hidratação removido"})
+}
+
+// GetHydrationSummary retorna os registros do dia (?date=YYYY-MM-DD, padrão hoje)
+// com o total bebido, a água vinda dos alimentos e a meta
+func GetHydrationSummary(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+		return
+	}
+
+	day, err := parseDateIn(c.Query("date"), user.Location())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Data inválida, use o formato AAAA-MM-DD"})
+		return
+	}
+
+	summary, entries, err := models.BuildHydrationSummary(database.DB, user, day)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao calcular hidratação"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"summary": summary,
+		"entries": entries,
+	})
+}
+
+// UpdateHydrationGoal define a meta diária de água; zero volta a derivar do peso
+func UpdateHydrationGoal(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	var request struct {
+		WaterGoalML float64 `json:"water_goal_ml"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if request.WaterGoalML < 0 || request.WaterGoalML > 10000 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Meta de água inválida"})
+		return
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+		return
+	}
+
+	user.WaterGoalML = request.WaterGoalML
+	if err := database.DB.Model(&user).Update("water_goal_ml", user.WaterGoalML).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar meta de água"})
+		return
+	}
+
+	goal, source := user.WaterGoal()
+	c.JSON(http.StatusOK, gin.H{"goal_ml": goal, "goal_source": source})
+}
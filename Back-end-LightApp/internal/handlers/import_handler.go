@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juliapinheiro42/LightApp/database"
+	"github.com/juliapinheiro42/LightApp/internal/importer"
+	"github.com/juliapinheiro42/LightApp/internal/models"
+)
+
+// Limites da importação de CSV
+const (
+	maxCSVImportSize   = 32 << 20
+	importPreviewMeals = 20
+	importMaxWarnings  = 100
+)
+
+// ImportCSV importa o diário exportado por outro aplicativo (MyFitnessPal,
+// FatSecret ou CSV parecido), enviado no campo file. Com ?dry_run=true, só
+// retorna a prévia do que seria gravado. ?date_order=dmy|mdy define a leitura
+// de datas com barras (padrão: dmy) e ?match_foods=false grava tudo como
+// registro rápido. Refeições já importadas são ignoradas.
+func ImportCSV(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	dryRun := c.Query("dry_run") == "true"
+	dateOrder := c.DefaultQuery("date_order", importer.DateOrderDMY)
+	if dateOrder != importer.DateOrderDMY && dateOrder != importer.DateOrderMDY {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Ordem de data inválida (use dmy ou mdy)"})
+		return
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Envie o arquivo CSV no campo file"})
+		return
+	}
+	if header.Size > maxCSVImportSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Arquivo muito grande"})
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Falha ao ler o arquivo"})
+		return
+	}
+	defer file.Close()
+
+	entries, source, warnings, err := importer.Parse(file, dateOrder)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	foods, err := models.CatalogFoods(database.DB)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao carregar alimentos"})
+		return
+	}
+
+	plan, err := importer.Build(database.DB, user.ID, entries, foods, importer.Options{
+		Source:     source,
+		Location:   user.Location(),
+		MatchFoods: c.Query("match_foods") != "false",
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao analisar o arquivo"})
+		return
+	}
+	plan.Warnings = append(warnings, plan.Warnings...)
+	warningCount := len(plan.Warnings)
+	if warningCount > importMaxWarnings {
+		plan.Warnings = plan.Warnings[:importMaxWarnings]
+	}
+
+	if !dryRun {
+		if err := importer.Apply(database.DB, user.ID, plan); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gravar refeições importadas"})
+			return
+		}
+	}
+
+	preview := plan.Meals
+	if len(preview) > importPreviewMeals {
+		preview = preview[:importPreviewMeals]
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"dry_run":       dryRun,
+		"summary":       plan,
+		"warning_count": warningCount,
+		"preview":       preview,
+	})
+}
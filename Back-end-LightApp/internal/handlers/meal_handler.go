@@ -1,204 +1,265 @@
 package handlers
 
 import (
-	"fmt"
 	"net/http"
-	"time"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/juliapinheiro42/LightApp/database"
 	"github.com/juliapinheiro42/LightApp/internal/models"
+	"gorm.io/gorm"
 )
 
 func CreateMeal(c *gin.Context) {
 	var meal models.Meal
 	userID, exists := c.Get("user_id")
 	if !exists {
-		fmt.Println("user_id não encontrado no contexto") // Log para depuração
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
 		return
 	}
-	fmt.Println("user_id obtido do contexto:", userID) // Log para depuração
 
-	meal.UserID = userID.(uint)
 	if err := c.ShouldBindJSON(&meal); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	database.DB.Create(&meal)
+	// Dono, itens e foto não vêm do corpo: os itens passam por AddMealItem,
+	// que valida e grava os nutrientes, e a foto pelo upload
+	meal.ID = 0
+	meal.UserID = userID.(uint)
+	meal.Items = nil
+	meal.Photo = nil
+	meal.PlannedMealID = nil
+	if !models.IsValidMealType(meal.MealType) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Tipo de refeição inválido"})
+		return
+	}
+	if err := meal.ValidateBehavior(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&meal).Error; err != nil {
+			return err
+		}
+		// Registrar uma refeição encerra o jejum em andamento
+		brokenFast, err := models.BreakActiveFast(tx, meal)
+		if err != nil {
+			return err
+		}
+		meal.BrokenFast = brokenFast
+		return models.RefreshDailySummary(tx, meal.UserID, meal.CreatedAt)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao registrar refeição"})
+		return
+	}
+
 	c.JSON(http.StatusCreated, meal)
 }
 
-func AddMealItem(c *gin.Context) {
-	var mealItem models.MealItem
-	if err := c.ShouldBindJSON(&mealItem); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+// UpdateMealDetails altera anotação, fome, saciedade, contexto e humor da refeição
+func UpdateMealDetails(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
 		return
 	}
 
-	fmt.Println("MealID:", mealItem.MealID, "FoodID:", mealItem.FoodID)
-
 	var meal models.Meal
-	if err := database.DB.First(&meal, mealItem.MealID).Error; err != nil {
+	if err := database.DB.Where("id = ? AND user_id = ?", c.Param("meal_id"), userID).First(&meal).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Refeição não encontrada"})
 		return
 	}
 
-	var food models.Food
-	if err := database.DB.First(&food, mealItem.FoodID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Alimento não encontrado no TACO"})
+	var request struct {
+		MealType    *string   `json:"meal_type"`
+		Note        *string   `json:"note"`
+		Hunger      *int      `json:"hunger"`
+		Fullness    *int      `json:"fullness"`
+		ContextTags *[]string `json:"context_tags"`
+		Mood        *string   `json:"mood"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	database.DB.Create(&mealItem)
-	c.JSON(http.StatusCreated, gin.H{"message": "Alimento adicionado à refeição!"})
+	// Apenas os campos enviados são alterados
+	if request.MealType != nil {
+		meal.MealType = *request.MealType
+	}
+	if request.Note != nil {
+		meal.Note = *request.Note
+	}
+	if request.Hunger != nil {
+		meal.Hunger = request.Hunger
+	}
+	if request.Fullness != nil {
+		meal.Fullness = request.Fullness
+	}
+	if request.ContextTags != nil {
+		meal.ContextTags = *request.ContextTags
+	}
+	if request.Mood != nil {
+		meal.Mood = *request.Mood
+	}
+
+	if !models.IsValidMealType(meal.MealType) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Tipo de refeição inválido"})
+		return
+	}
+	if err := meal.ValidateBehavior(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := database.DB.Model(&meal).Select("meal_type", "note", "hunger", "fullness", "context_tags", "mood").Updates(&meal).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar refeição"})
+		return
+	}
+
+	c.JSON(http.StatusOK, meal)
 }
 
-func GetMealSummary(c *gin.Context) {
-	var mealItems []models.MealItem
-	mealID := c.Param("meal_id")
+func AddMealItem(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
 
-	if err := database.DB.Where("meal_id = ?", mealID).Find(&mealItems).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Refeição não encontrada"})
+	var mealItem models.MealItem
+	if err := c.ShouldBindJSON(&mealItem); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	fmt.Println("Itens encontrados para MealID", mealID, ":", len(mealItems))
 
-	var totalCalories, totalProteins, totalCarbs, totalFats float64
+	var meal models.Meal
+	if err := database.DB.Where("id = ? AND user_id = ?", mealItem.MealID, userID).First(&meal).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Refeição não encontrada"})
+		return
+	}
 
-	for _, item := range mealItems {
+	if mealItem.FoodID == nil {
+		// Registro rápido: calorias e macros informados pelo usuário, sem alimento
+		if err := mealItem.PrepareQuickAdd(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	} else {
 		var food models.Food
-		if err := database.DB.First(&food, item.FoodID).Error; err != nil {
-			fmt.Println("Erro ao buscar FoodID:", item.FoodID)
-			continue
+		if err := database.DB.First(&food, *mealItem.FoodID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Alimento não encontrado no TACO"})
+			return
 		}
 
-		factor := item.Amount / 100.0
-		fmt.Println("Food:", food.ID, "Calories:", food.Calories, "Factor:", factor)
+		// Sem unidade informada, a quantidade é a própria gramagem
+		if mealItem.Unit == "" {
+			mealItem.Unit = "g"
+		}
+		if mealItem.Quantity == 0 {
+			mealItem.Quantity = mealItem.Amount
+		}
 
-		totalCalories += food.Calories * factor
-		totalProteins += food.Protein * factor
-		totalCarbs += food.Carbs * factor
-		totalFats += food.Fat * factor
+		// Guarda os nutrientes atuais para que correções no catálogo não alterem o histórico
+		mealItem.QuickAdd = false
+		mealItem.Label = ""
+		mealItem.SnapshotFrom(food)
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"calories": totalCalories,
-		"proteins": totalProteins,
-		"carbs":    totalCarbs,
-		"fats":     totalFats,
+	mealItem.ID = 0
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&mealItem).Error; err != nil {
+			return err
+		}
+		return models.RefreshDailySummary(tx, meal.UserID, meal.CreatedAt)
 	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao adicionar alimento"})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "Alimento adicionado à refeição!"})
 }
 
-func GetDailySummary(c *gin.Context) {
+func GetMealSummary(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
 		return
 	}
 
-	// Formatar a data de hoje no formato YYYY-MM-DD
-	today := time.Now().Format("2006-01-02")
-
-	// Buscar todas as refeições do usuário para o dia atual
-	var meals []models.Meal
-	if err := database.DB.Where("user_id = ? AND DATE(created_at) = ?", userID, today).Find(&meals).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Nenhuma refeição encontrada para hoje"})
+	mealID, err := strconv.ParseUint(c.Param("meal_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Refeição não encontrada"})
 		return
 	}
 
-	// Inicializar totais
-	var totalCalories, totalProteins, totalCarbs, totalFats float64
-
-	// Iterar sobre as refeições
-	for _, meal := range meals {
-		// Buscar os itens da refeição
-		var mealItems []models.MealItem
-		if err := database.DB.Where("meal_id = ?", meal.ID).Find(&mealItems).Error; err != nil {
-			fmt.Println("Erro ao buscar itens da refeição:", err)
-			continue
-		}
-
-		// Iterar sobre os itens da refeição
-		for _, item := range mealItems {
-			// Buscar o alimento associado ao item
-			var food models.Food
-			if err := database.DB.First(&food, item.FoodID).Error; err != nil {
-				fmt.Println("Erro ao buscar alimento:", err)
-				continue
-			}
-
-			// Calcular o fator de proporção (quantidade em gramas / 100)
-			factor := item.Amount / 100.0
-
-			// Acumular os valores nutricionais
-			totalCalories += food.Calories * factor
-			totalProteins += food.Protein * factor
-			totalCarbs += food.Carbs * factor
-			totalFats += food.Fat * factor
-		}
+	total, err := models.AggregateNutrients(database.DB, models.NutrientFilter{UserID: userID.(uint), MealID: uint(mealID)})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao calcular resumo da refeição"})
+		return
+	}
+	if total.Meals == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Refeição não encontrada"})
+		return
 	}
 
-	// Retornar o resumo diário
 	c.JSON(http.StatusOK, gin.H{
-		"date":     today,
-		"calories": totalCalories,
-		"proteins": totalProteins,
-		"carbs":    totalCarbs,
-		"fats":     totalFats,
+		"calories":           total.Calories,
+		"proteins":           total.Protein,
+		"carbs":              total.Carbs,
+		"fats":               total.Fat,
+		"quick_add_calories": total.QuickAddCalories,
 	})
 }
 
-func GetWeeklySummary(c *gin.Context) {
+func GetDailySummary(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
 		return
 	}
 
-	oneWeekAgo := time.Now().AddDate(0, 0, -7)
-	today := time.Now()
-
-	dailySummary := make(map[string]map[string]float64)
-
-	for i := 0; i < 7; i++ {
-		date := oneWeekAgo.AddDate(0, 0, i).Format("2006-01-02")
-		dailySummary[date] = map[string]float64{
-			"calories": 0,
-			"proteins": 0,
-			"carbs":    0,
-			"fats":     0,
-		}
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+		return
 	}
 
-	var meals []models.Meal
-	if err := database.DB.Where("user_id = ? AND DATE(created_at) BETWEEN ? AND ?", userID, oneWeekAgo, today).Find(&meals).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Nenhuma refeição encontrada na última semana"})
+	// Refeições de hoje, no fuso do usuário
+	today, _ := parseDateIn("", user.Location())
+	total, err := models.AggregateNutrients(database.DB, models.NutrientFilter{
+		UserID: userID.(uint),
+		From:   today,
+		To:     today.AddDate(0, 0, 1),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao calcular resumo diário"})
 		return
 	}
 
-	for _, meal := range meals {
-		date := meal.CreatedAt.Format("2006-01-02")
-
-		var mealItems []models.MealItem
-		database.DB.Where("meal_id = ?", meal.ID).Find(&mealItems)
-
-		for _, item := range mealItems {
-			var food models.Food
-			database.DB.First(&food, item.FoodID)
-
-			factor := item.Amount / 100.0
-			dailySummary[date]["calories"] += food.Calories * factor
-			dailySummary[date]["proteins"] += food.Protein * factor
-			dailySummary[date]["carbs"] += food.Carbs * factor
-			dailySummary[date]["fats"] += food.Fat * factor
+	// Progresso nas metas do perfil; sem perfil completo, não há metas
+	var progress *models.DailyProgress
+	if user.ApplyEnergyData(database.DB) == nil {
+		if targets, err := user.NutritionTargets(); err == nil {
+			dailyProgress := models.BuildDailyProgress(targets, total.Nutrients)
+			progress = &dailyProgress
 		}
 	}
 
+	// Retornar o resumo diário
 	c.JSON(http.StatusOK, gin.H{
-		"week_start": oneWeekAgo.Format("2006-01-02"),
-		"week_end":   today.Format("2006-01-02"),
-		"daily_data": dailySummary,
+		"date":               today.Format(models.DateLayout),
+		"calories":           total.Calories,
+		"proteins":           total.Protein,
+		"carbs":              total.Carbs,
+		"fats":               total.Fat,
+		"fiber":              total.Fiber,
+		"water_from_food":    total.Water,
+		"quick_add_calories": total.QuickAddCalories,
+		"meals":              total.Meals,
+		"progress":           progress,
 	})
 }
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juliapinheiro42/LightApp/database"
+	"github.com/juliapinheiro42/LightApp/internal/models"
+)
+
+// Paginação do histórico de refeições
+const (
+	defaultMealHistoryLimit = 50
+	maxMealHistoryLimit     = 200
+)
+
+// ListMeals retorna o histórico de refeições com filtros opcionais:
+// from, to (AAAA-MM-DD), meal_type, context, mood, hunger_min, hunger_max,
+// fullness_min, fullness_max, has_note, limit e offset
+func ListMeals(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	var user models.User
+	if err := database.DB.Select("id", "time_zone").First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+		return
+	}
+
+	// Datas no fuso do usuário
+	query := database.DB.Model(&models.Meal{}).Where("user_id = ?", userID)
+
+	if from := c.Query("from"); from != "" {
+		day, err := parseDateIn(from, user.Location())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Data inicial inválida, use o formato AAAA-MM-DD"})
+			return
+		}
+		query = query.Where("created_at >= ?", day)
+	}
+	if to := c.Query("to"); to != "" {
+		day, err := parseDateIn(to, user.Location())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Data final inválida, use o formato AAAA-MM-DD"})
+			return
+		}
+		query = query.Where("created_at < ?", day.AddDate(0, 0, 1))
+	}
+
+	if mealType := c.Query("meal_type"); mealType != "" {
+		if !models.IsValidMealType(mealType) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Tipo de refeição inválido"})
+			return
+		}
+		query = query.Where("meal_type = ?", mealType)
+	}
+	if context := c.Query("context"); context != "" {
+		if !slices.Contains(models.MealContextTags, context) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Contexto inválido"})
+			return
+		}
+		// context_tags é gravado como lista JSON
+		query = query.Where("context_tags LIKE ?", `%"`+context+`"%`)
+	}
+	if mood := c.Query("mood"); mood != "" {
+		if !slices.Contains(models.MealMoods, mood) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Humor inválido"})
+			return
+		}
+		query = query.Where("mood = ?", mood)
+	}
+	if c.Query("has_note") == "true" {
+		query = query.Where("note <> ''")
+	}
+
+	ranges := []struct {
+		param, condition string
+	}{
+		{"hunger_min", "hunger >= ?"},
+		{"hunger_max", "hunger <= ?"},
+		{"fullness_min", "fullness >= ?"},
+		{"fullness_max", "fullness <= ?"},
+	}
+	for _, r := range ranges {
+		value := c.Query(r.param)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > 5 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Valor inválido para " + r.param + " (de 1 a 5)"})
+			return
+		}
+		query = query.Where(r.condition, parsed)
+	}
+
+	limit, offset := defaultMealHistoryLimit, 0
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Limite inválido"})
+			return
+		}
+		limit = min(parsed, maxMealHistoryLimit)
+	}
+	if value := c.Query("offset"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Deslocamento inválido"})
+			return
+		}
+		offset = parsed
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar refeições"})
+		return
+	}
+
+	var meals []models.Meal
+	if err := query.Preload("Items").Preload("Photo").Order("created_at DESC").Limit(limit).Offset(offset).Find(&meals).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar refeições"})
+		return
+	}
+	for _, meal := range meals {
+		if meal.Photo != nil {
+			signPhotoURLs(meal.Photo)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"meals":  meals,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juliapinheiro42/LightApp/database"
+	"github.com/juliapinheiro42/LightApp/internal/mealparser"
+	"github.com/juliapinheiro42/LightApp/internal/models"
+)
+
+// Tamanho máximo da frase aceita para interpretação
+const maxMealTextLength = 500
+
+// ParseMealText interpreta uma frase como "2 ovos mexidos e 1 concha de feijão"
+// e devolve os itens propostos, sem gravar nada. O usuário confirma cada item
+// e o envia para /meals/items.
+func ParseMealText(c *gin.Context) {
+	var request struct {
+		Text string `json:"text" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Informe o texto da refeição"})
+		return
+	}
+
+	text := strings.TrimSpace(request.Text)
+	if text == "" || len(text) > maxMealTextLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Texto da refeição inválido"})
+		return
+	}
+
+	foods, err := models.CatalogFoods(database.DB)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao carregar alimentos"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": mealparser.Propose(text, foods)})
+}
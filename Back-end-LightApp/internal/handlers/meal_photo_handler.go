@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	_ "image/png" // Decodificação de fotos PNG
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/juliapinheiro42/LightApp/database"
+	"github.com/juliapinheiro42/LightApp/internal/imaging"
+	"github.com/juliapinheiro42/LightApp/internal/models"
+	"github.com/juliapinheiro42/LightApp/internal/storage"
+	"github.com/juliapinheiro42/LightApp/internal/utils"
+)
+
+// Limites das fotos de refeição
+const (
+	maxMealPhotoBytes  = 10 << 20
+	maxMealPhotoPixels = 40_000_000
+	thumbnailSide      = 320
+	photoURLLifetime   = 15 * time.Minute
+)
+
+// Tipos de imagem aceitos e a extensão usada na chave
+var mealPhotoTypes = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+}
+
+// UploadMealPhoto anexa uma foto (campo "image") à refeição, substituindo a
+// anterior. A miniatura é gerada no servidor.
+func UploadMealPhoto(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	var meal models.Meal
+	if err := database.DB.Where("id = ? AND user_id = ?", c.Param("meal_id"), userID).First(&meal).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Refeição não encontrada"})
+		return
+	}
+
+	file, err := c.FormFile("image")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Falha ao obter a imagem"})
+		return
+	}
+	if file.Size > maxMealPhotoBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Imagem maior que 10 MB"})
+		return
+	}
+	opened, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Falha ao ler a imagem"})
+		return
+	}
+	defer opened.Close()
+	data, err := io.ReadAll(io.LimitReader(opened, maxMealPhotoBytes+1))
+	if err != nil || len(data) > maxMealPhotoBytes {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Falha ao ler a imagem"})
+		return
+	}
+
+	// O tipo vem do conteúdo, não do nome ou do cabeçalho enviados
+	contentType := http.DetectContentType(data)
+	extension, ok := mealPhotoTypes[contentType]
+	if !ok {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "Formato de imagem não suportado (use JPEG ou PNG)"})
+		return
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || config.Width*config.Height > maxMealPhotoPixels {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Imagem inválida ou grande demais"})
+		return
+	}
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Imagem inválida"})
+		return
+	}
+
+	var thumbnail bytes.Buffer
+	if err := jpeg.Encode(&thumbnail, imaging.Thumbnail(decoded, thumbnailSide), &jpeg.Options{Quality: 80}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Falha ao gerar miniatura"})
+		return
+	}
+
+	name := uuid.New().String()
+	prefix := fmt.Sprintf("meals/%d/%d/", meal.UserID, meal.ID)
+	photo := models.MealPhoto{
+		MealID:       meal.ID,
+		UserID:       meal.UserID,
+		Key:          prefix + name + "." + extension,
+		ThumbnailKey: prefix + name + "_thumb.jpg",
+		ContentType:  contentType,
+		Size:         int64(len(data)),
+		Width:        config.Width,
+		Height:       config.Height,
+	}
+
+	ctx := c.Request.Context()
+	if err := storage.Default.Put(ctx, photo.Key, bytes.NewReader(data), photo.Size, contentType); err != nil {
+		log.Println("Erro ao salvar foto:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Falha ao salvar a imagem"})
+		return
+	}
+	if err := storage.Default.Put(ctx, photo.ThumbnailKey, &thumbnail, int64(thumbnail.Len()), "image/jpeg"); err != nil {
+		log.Println("Erro ao salvar miniatura:", err)
+		_ = storage.Default.Delete(ctx, photo.Key)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Falha ao salvar a imagem"})
+		return
+	}
+
+	var previous models.MealPhoto
+	hasPrevious := database.DB.Where("meal_id = ?", meal.ID).Limit(1).Find(&previous).RowsAffected > 0
+	if hasPrevious {
+		photo.ID = previous.ID
+	}
+	if err := database.DB.Save(&photo).Error; err != nil {
+		_ = storage.Default.Delete(ctx, photo.Key)
+		_ = storage.Default.Delete(ctx, photo.ThumbnailKey)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao salvar foto da refeição"})
+		return
+	}
+	if hasPrevious {
+		deletePhotoObjects(c, previous)
+	}
+
+	signPhotoURLs(&photo)
+	c.JSON(http.StatusCreated, photo)
+}
+
+// GetMealPhoto retorna os dados da foto com URLs assinadas de curta duração
+func GetMealPhoto(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	var photo models.MealPhoto
+	if err := database.DB.Where("meal_id = ? AND user_id = ?", c.Param("meal_id"), userID).First(&photo).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Foto não encontrada"})
+		return
+	}
+
+	signPhotoURLs(&photo)
+	c.JSON(http.StatusOK, photo)
+}
+
+func DeleteMealPhoto(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	var photo models.MealPhoto
+	if err := database.DB.Where("meal_id = ? AND user_id = ?", c.Param("meal_id"), userID).First(&photo).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Foto não encontrada"})
+		return
+	}
+	if err := database.DB.Delete(&photo).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao remover foto"})
+		return
+	}
+	deletePhotoObjects(c, photo)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Foto removida"})
+}
+
+// ServeMealPhoto entrega a imagem a partir de uma URL assinada. A rota não
+// exige token, para funcionar direto em componentes de imagem do app; a
+// assinatura só é emitida para o dono da refeição e expira.
+func ServeMealPhoto(c *gin.Context) {
+	photoID, err := strconv.ParseUint(c.Param("photo_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Foto não encontrada"})
+		return
+	}
+	variant := c.Param("variant")
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil || time.Now().Unix() > expires ||
+		!hmac.Equal([]byte(c.Query("signature")), []byte(photoSignature(uint(photoID), variant, expires))) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Link da foto inválido ou expirado"})
+		return
+	}
+
+	var photo models.MealPhoto
+	if err := database.DB.First(&photo, photoID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Foto não encontrada"})
+		return
+	}
+	key, ok := photo.KeyFor(variant)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Foto não encontrada"})
+		return
+	}
+	contentType := photo.ContentType
+	if variant == models.PhotoVariantThumbnail {
+		contentType = "image/jpeg"
+	}
+
+	body, err := storage.Default.Get(c.Request.Context(), key)
+	if errors.Is(err, storage.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Foto não encontrada"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Falha ao ler a imagem"})
+		return
+	}
+	defer body.Close()
+
+	c.Header("Cache-Control", "private, max-age="+strconv.Itoa(int(photoURLLifetime.Seconds())))
+	c.DataFromReader(http.StatusOK, -1, contentType, body, nil)
+}
+
+// signPhotoURLs preenche as URLs assinadas da foto e da miniatura
+func signPhotoURLs(photo *models.MealPhoto) {
+	expires := time.Now().Add(photoURLLifetime).Unix()
+	url := func(variant string) string {
+		return fmt.Sprintf("/api/photos/%d/%s?expires=%d&signature=%s",
+			photo.ID, variant, expires, photoSignature(photo.ID, variant, expires))
+	}
+	photo.URL = url(models.PhotoVariantOriginal)
+	photo.ThumbnailURL = url(models.PhotoVariantThumbnail)
+}
+
+func photoSignature(photoID uint, variant string, expires int64) string {
+	secret := []byte(os.Getenv("PHOTO_URL_SECRET"))
+	if len(secret) == 0 {
+		secret = utils.SecretKey
+	}
+	mac := hmac.New(sha256.New, secret)
+	fmt.Fprintf(mac, "%d:%s:%d", photoID, variant, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// deletePhotoObjects remove os arquivos da foto; falhas só são registradas,
+// pois o registro no banco já foi removido ou substituído
+func deletePhotoObjects(c *gin.Context, photo models.MealPhoto) {
+	for _, key := range []string{photo.Key, photo.ThumbnailKey} {
+		if err := storage.Default.Delete(c.Request.Context(), key); err != nil {
+			log.Println("Erro ao remover arquivo da foto:", err)
+		}
+	}
+}
@@ -0,0 +1,388 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juliapinheiro42/LightApp/database"
+	"github.com/juliapinheiro42/LightApp/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Ordem dos tipos de refeição na comparação do dia
+var mealTypeOrder = []string{
+	models.MealTypeBreakfast,
+	models.MealTypeLunch,
+	models.MealTypeSnack,
+	models.MealTypeDinner,
+	"",
+}
+
+type mealPlanRequest struct {
+	Name      string               `json:"name" binding:"required"`
+	StartDate string               `json:"start_date"`
+	EndDate   string               `json:"end_date"`
+	Meals     []models.PlannedMeal `json:"meals"`
+}
+
+// CreateMealPlan cria um plano para o próprio usuário ou, em
+// /clients/:client_id/meal-plans, para o cliente que deu acesso ao nutricionista
+func CreateMealPlan(c *gin.Context) {
+	ownerID, ok := planOwner(c)
+	if !ok {
+		return
+	}
+
+	var request mealPlanRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	loc, err := models.UserLocation(database.DB, ownerID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+		return
+	}
+
+	// Datas no fuso do dono do plano
+	startDate, err := parseDateIn(request.StartDate, loc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Data inicial inválida, use o formato AAAA-MM-DD"})
+		return
+	}
+
+	plan := models.MealPlan{
+		UserID:    ownerID,
+		CreatedBy: c.GetUint("user_id"),
+		Name:      strings.TrimSpace(request.Name),
+		StartDate: startDate,
+		Meals:     request.Meals,
+	}
+
+	if request.EndDate != "" {
+		endDate, err := parseDateIn(request.EndDate, loc)
+		if err != nil || endDate.Before(startDate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Data final inválida"})
+			return
+		}
+		plan.EndDate = &endDate
+	}
+
+	for i := range plan.Meals {
+		meal := &plan.Meals[i]
+		meal.ID = 0
+		if err := meal.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		for j := range meal.Items {
+			meal.Items[j].ID = 0
+			if err := meal.Items[j].Prepare(database.DB); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+	}
+
+	if err := database.DB.Create(&plan).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar plano alimentar"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, plan)
+}
+
+func ListMealPlans(c *gin.Context) {
+	ownerID, ok := planOwner(c)
+	if !ok {
+		return
+	}
+
+	var plans []models.MealPlan
+	if err := database.DB.Where("user_id = ?", ownerID).Order("start_date DESC").Find(&plans).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar planos alimentares"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"plans": plans})
+}
+
+func GetMealPlan(c *gin.Context) {
+	ownerID, ok := planOwner(c)
+	if !ok {
+		return
+	}
+
+	plan, err := findMealPlan(ownerID, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Plano alimentar não encontrado"})
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
+
+func DeleteMealPlan(c *gin.Context) {
+	ownerID, ok := planOwner(c)
+	if !ok {
+		return
+	}
+
+	result := database.DB.Where("id = ? AND user_id = ?", c.Param("id"), ownerID).Delete(&models.MealPlan{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao remover plano alimentar"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Plano alimentar não encontrado"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Plano alimentar removido"})
+}
+
+// GetMealPlanDay compara o planejado para a data (?date=AAAA-MM-DD) com o que
+// foi registrado, por tipo de refeição e no total do dia
+func GetMealPlanDay(c *gin.Context) {
+	ownerID, ok := planOwner(c)
+	if !ok {
+		return
+	}
+
+	loc, err := models.UserLocation(database.DB, ownerID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+		return
+	}
+
+	// Dia no fuso do dono do plano, como nos resumos diários
+	day, err := parseDateIn(c.Query("date"), loc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Data inválida, use o formato AAAA-MM-DD"})
+		return
+	}
+
+	plan, err := findMealPlan(ownerID, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Plano alimentar não encontrado"})
+		return
+	}
+
+	var logged []models.Meal
+	if err := database.DB.Preload("Items").
+		Where("user_id = ? AND created_at >= ? AND created_at < ?", ownerID, day, day.AddDate(0, 0, 1)).
+		Order("created_at").Find(&logged).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar refeições do dia"})
+		return
+	}
+
+	type mealComparison struct {
+		MealType     string               `json:"meal_type"`
+		Planned      models.Nutrients     `json:"planned"`
+		Actual       models.Nutrients     `json:"actual"`
+		Difference   models.Nutrients     `json:"difference"` // Registrado menos planejado
+		Eaten        bool                 `json:"eaten"`
+		PlannedMeals []models.PlannedMeal `json:"planned_meals"`
+		LoggedMeals  []models.Meal        `json:"logged_meals"`
+	}
+
+	byType := make(map[string]*mealComparison)
+	comparisonFor := func(mealType string) *mealComparison {
+		if byType[mealType] == nil {
+			byType[mealType] = &mealComparison{
+				MealType:     mealType,
+				PlannedMeals: []models.PlannedMeal{},
+				LoggedMeals:  []models.Meal{},
+			}
+		}
+		return byType[mealType]
+	}
+
+	eatenPlanned := make(map[uint]bool)
+	for _, meal := range logged {
+		comparison := comparisonFor(meal.MealType)
+		comparison.LoggedMeals = append(comparison.LoggedMeals, meal)
+		for _, item := range meal.Items {
+			comparison.Actual = comparison.Actual.Add(item.Nutrients)
+		}
+		if meal.PlannedMealID != nil {
+			eatenPlanned[*meal.PlannedMealID] = true
+		}
+	}
+
+	for _, planned := range plan.MealsFor(day) {
+		comparison := comparisonFor(planned.MealType)
+		comparison.PlannedMeals = append(comparison.PlannedMeals, planned)
+		comparison.Planned = comparison.Planned.Add(planned.Totals())
+		if eatenPlanned[planned.ID] {
+			comparison.Eaten = true
+		}
+	}
+
+	var plannedTotal, actualTotal models.Nutrients
+	meals := []mealComparison{}
+	for _, mealType := range mealTypeOrder {
+		comparison, ok := byType[mealType]
+		if !ok {
+			continue
+		}
+		comparison.Difference = comparison.Actual.Sub(comparison.Planned)
+		plannedTotal = plannedTotal.Add(comparison.Planned)
+		actualTotal = actualTotal.Add(comparison.Actual)
+		meals = append(meals, *comparison)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"date":       day.Format(models.DateLayout),
+		"weekday":    int(day.Weekday()),
+		"in_plan":    plan.CoversDate(day),
+		"meals":      meals,
+		"planned":    plannedTotal,
+		"actual":     actualTotal,
+		"difference": actualTotal.Sub(plannedTotal),
+	})
+}
+
+var (
+	errPlannedMealEaten   = errors.New("Refeição planejada já registrada nesta data")
+	errPlannedFoodMissing = errors.New("Alimento do plano não encontrado no catálogo")
+)
+
+// MarkPlannedMealEaten transforma a refeição planejada em Meal e MealItems
+// reais para a data informada, em uma única transação
+func MarkPlannedMealEaten(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	var request struct {
+		Date string `json:"date"`
+	}
+	// Corpo opcional: sem data, a refeição é registrada hoje
+	_ = c.ShouldBindJSON(&request)
+
+	loc, err := models.UserLocation(database.DB, userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+		return
+	}
+
+	// Sem data, hoje no fuso do usuário
+	day, err := parseDateIn(request.Date, loc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Data inválida, use o formato AAAA-MM-DD"})
+		return
+	}
+
+	plan, err := findMealPlan(userID.(uint), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Plano alimentar não encontrado"})
+		return
+	}
+
+	plannedMealID, _ := strconv.ParseUint(c.Param("planned_meal_id"), 10, 64)
+	var planned *models.PlannedMeal
+	for i := range plan.Meals {
+		if uint64(plan.Meals[i].ID) == plannedMealID {
+			planned = &plan.Meals[i]
+		}
+	}
+	if planned == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Refeição planejada não encontrada"})
+		return
+	}
+
+	if !plan.CoversDate(day) || planned.Weekday != int(day.Weekday()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A refeição planejada não está prevista para esta data"})
+		return
+	}
+
+	meal := models.Meal{
+		UserID:        userID.(uint),
+		MealType:      planned.MealType,
+		PlannedMealID: &planned.ID,
+		CreatedAt:     plannedMealTime(day, planned.Time),
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		// O bloqueio da refeição planejada serializa marcações simultâneas
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&models.PlannedMeal{}, planned.ID).Error; err != nil {
+			return err
+		}
+		var alreadyEaten int64
+		if err := tx.Model(&models.Meal{}).
+			Where("planned_meal_id = ? AND created_at >= ? AND created_at < ?", planned.ID, day, day.AddDate(0, 0, 1)).
+			Count(&alreadyEaten).Error; err != nil {
+			return err
+		}
+		if alreadyEaten > 0 {
+			return errPlannedMealEaten
+		}
+
+		if err := tx.Create(&meal).Error; err != nil {
+			return err
+		}
+		for _, plannedItem := range planned.Items {
+			item, err := plannedItem.ToMealItem(tx, meal.ID)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errPlannedFoodMissing
+			}
+			if err != nil {
+				return err
+			}
+			if err := tx.Create(&item).Error; err != nil {
+				return err
+			}
+			meal.Items = append(meal.Items, item)
+		}
+		if err := models.RefreshDailySummary(tx, meal.UserID, meal.CreatedAt); err != nil {
+			return err
+		}
+
+		brokenFast, err := models.BreakActiveFast(tx, meal)
+		meal.BrokenFast = brokenFast
+		return err
+	})
+	switch {
+	case errors.Is(err, errPlannedMealEaten):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, errPlannedFoodMissing):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao registrar refeição planejada"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, meal)
+}
+
+func findMealPlan(userID uint, planID string) (models.MealPlan, error) {
+	var plan models.MealPlan
+	err := database.DB.Preload("Meals.Items").
+		Where("id = ? AND user_id = ?", planID, userID).
+		First(&plan).Error
+	return plan, err
+}
+
+// plannedMealTime define o horário do registro: o horário planejado, o momento
+// atual quando a data é hoje, ou meio-dia
+func plannedMealTime(day time.Time, plannedTime string) time.Time {
+	if parsed, err := time.Parse("15:04", plannedTime); err == nil {
+		return time.Date(day.Year(), day.Month(), day.Day(), parsed.Hour(), parsed.Minute(), 0, 0, day.Location())
+	}
+	now := time.Now().In(day.Location())
+	if day.Format(models.DateLayout) == now.Format(models.DateLayout) {
+		return now
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), 12, 0, 0, 0, day.Location())
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juliapinheiro42/LightApp/database"
+	"github.com/juliapinheiro42/LightApp/internal/models"
+)
+
+type measurementRequest struct {
+	Waist      *float64   `json:"waist"`
+	Hip        *float64   `json:"hip"`
+	Neck       *float64   `json:"neck"`
+	BodyFat    *float64   `json:"body_fat"`
+	MeasuredAt *time.Time `json:"measured_at"`
+	Note       string     `json:"note"`
+}
+
+// apply copia os dados da requisição para a medida e valida o resultado
+func (r measurementRequest) apply(m *models.BodyMeasurement) string {
+	if r.MeasuredAt != nil && r.MeasuredAt.After(time.Now()) {
+		return "A medida não pode estar no futuro"
+	}
+	if len(r.Note) > models.MaxMealNoteLength {
+		return "Anotação muito longa"
+	}
+
+	m.Waist, m.Hip, m.Neck, m.BodyFat = r.Waist, r.Hip, r.Neck, r.BodyFat
+	m.Note = strings.TrimSpace(r.Note)
+	if r.MeasuredAt != nil {
+		m.MeasuredAt = *r.MeasuredAt
+	}
+	if err := m.Validate(); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// measurementView é a medida com as métricas derivadas
+type measurementView struct {
+	models.BodyMeasurement
+	Composition models.BodyComposition `json:"composition"`
+}
+
+func LogMeasurement(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+		return
+	}
+
+	var request measurementRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	measurement := models.BodyMeasurement{UserID: user.ID, MeasuredAt: time.Now()}
+	if msg := request.apply(&measurement); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	if err := database.DB.Create(&measurement).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao registrar medida"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, measurementView{measurement, user.BodyComposition(measurement)})
+}
+
+func UpdateMeasurement(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+		return
+	}
+
+	var measurement models.BodyMeasurement
+	if err := database.DB.Where("id = ? AND user_id = ?", c.Param("id"), user.ID).First(&measurement).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Medida não encontrada"})
+		return
+	}
+
+	var request measurementRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if msg := request.apply(&measurement); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	if err := database.DB.Save(&measurement).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar medida"})
+		return
+	}
+
+	c.JSON(http.StatusOK, measurementView{measurement, user.BodyComposition(measurement)})
+}
+
+func DeleteMeasurement(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	result := database.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).Delete(&models.BodyMeasurement{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao remover medida"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Medida não encontrada"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Medida removida"})
+}
+
+// GetMeasurements retorna as medidas do período (?from=&to=, padrão: últimos
+// 180 dias) com as métricas de cada uma e a composição corporal atual, que
+// combina a medida mais recente de cada campo com o peso do perfil
+func GetMeasurements(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+		return
+	}
+	if _, err := user.ApplyLatestWeight(database.DB); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar pesagens"})
+		return
+	}
+	loc := user.Location()
+
+	from, to, err := parseDateRange(c, loc, 180)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var measurements []models.BodyMeasurement
+	if err := database.DB.Where("user_id = ? AND measured_at >= ? AND measured_at < ?", user.ID, from, to.AddDate(0, 0, 1)).
+		Order("measured_at, id").Find(&measurements).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar medidas"})
+		return
+	}
+	entries := make([]measurementView, 0, len(measurements))
+	for _, m := range measurements {
+		entries = append(entries, measurementView{m, user.BodyComposition(m)})
+	}
+
+	response := gin.H{
+		"from":      from.Format(models.DateLayout),
+		"to":        to.Format(models.DateLayout),
+		"time_zone": loc.String(),
+		"entries":   entries,
+		"current":   nil,
+	}
+
+	current, ok, err := models.CurrentMeasurement(database.DB, user.ID, time.Now().Add(-models.BodyMeasurementValidity))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar medidas"})
+		return
+	}
+	if ok {
+		response["current"] = measurementView{current, user.BodyComposition(current)}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juliapinheiro42/LightApp/database"
+	"github.com/juliapinheiro42/LightApp/internal/dri"
+	"github.com/juliapinheiro42/LightApp/internal/models"
+)
+
+// Com poucos dias registrados, a média não representa a ingestão habitual
+const minMicronutrientReportDays = 3
+
+// GetMicronutrientReport compara a ingestão média diária de micronutrientes
+// no período (?from=&to=, padrão: últimos 30 dias) com as DRIs da idade e do
+// sexo do perfil. A média considera apenas os dias com registro.
+func GetMicronutrientReport(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+		return
+	}
+	if user.Age == 0 || user.Sex == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Idade e sexo precisam ser cadastrados"})
+		return
+	}
+
+	loc := user.Location()
+	from, to, err := parseDateRange(c, loc, 30)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	table, err := dri.Load()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao carregar valores de referência"})
+		return
+	}
+
+	totals, err := models.SummaryTotals(database.DB, user.ID, from.Format(models.DateLayout), to.Format(models.DateLayout))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao calcular ingestão"})
+		return
+	}
+
+	period := models.MergeSummaryBuckets(models.BuildSummaryBuckets(totals, from, to, models.SummaryGroupDay))
+	assessments, err := table.Assess(period.Average.Micronutrients.Values(), user.Age, user.Sex)
+	if errors.Is(err, dri.ErrNoReference) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao avaliar ingestão"})
+		return
+	}
+
+	// Destaques para a tela: prováveis deficiências e excessos
+	deficiencies, excesses := []string{}, []string{}
+	for _, assessment := range assessments {
+		switch assessment.Status {
+		case dri.StatusLikelyInadequate:
+			deficiencies = append(deficiencies, assessment.Nutrient)
+		case dri.StatusAboveUL:
+			excesses = append(excesses, assessment.Nutrient)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"from":              from.Format(models.DateLayout),
+		"to":                to.Format(models.DateLayout),
+		"days":              period.Days,
+		"logged_days":       period.LoggedDays,
+		"low_confidence":    period.LoggedDays < minMicronutrientReportDays,
+		"reference_version": table.Version,
+		"reference_source":  table.Source,
+		"age":               user.Age,
+		"sex":               user.Sex,
+		"deficiencies":      deficiencies,
+		"excesses":          excesses,
+		"nutrients":         assessments,
+	})
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juliapinheiro42/LightApp/database"
+	"github.com/juliapinheiro42/LightApp/internal/models"
+	"gorm.io/gorm/clause"
+)
+
+// GrantNutritionistAccess dá ao nutricionista, identificado pelo e-mail da
+// conta dele, acesso aos planos alimentares do usuário
+func GrantNutritionistAccess(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	var request struct {
+		Email string `json:"email" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Informe o e-mail do nutricionista"})
+		return
+	}
+
+	var nutritionist models.User
+	if err := database.DB.Where("email = ? AND deletion_scheduled_at IS NULL", strings.TrimSpace(request.Email)).
+		First(&nutritionist).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Nenhuma conta encontrada com este e-mail"})
+		return
+	}
+	if nutritionist.ID == userID.(uint) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Não é possível dar acesso à própria conta"})
+		return
+	}
+
+	access := models.NutritionistAccess{ClientID: userID.(uint), NutritionistID: nutritionist.ID}
+	if err := database.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&access).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao conceder acesso"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Acesso concedido", "nutritionist_id": nutritionist.ID})
+}
+
+// ListNutritionists lista as contas com acesso aos dados do usuário
+func ListNutritionists(c *gin.Context) {
+	listAccessContacts(c, false)
+}
+
+// ListClients lista os usuários que deram acesso ao nutricionista
+func ListClients(c *gin.Context) {
+	listAccessContacts(c, true)
+}
+
+func listAccessContacts(c *gin.Context, asNutritionist bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	contacts, err := models.ListAccessContacts(database.DB, userID.(uint), asNutritionist)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar acessos"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"accounts": contacts})
+}
+
+// RevokeNutritionistAccess remove o acesso; vale para os dois lados
+func RevokeNutritionistAccess(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	result := database.DB.Where("id = ? AND (client_id = ? OR nutritionist_id = ?)", c.Param("id"), userID, userID).
+		Delete(&models.NutritionistAccess{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao remover acesso"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Acesso não encontrado"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Acesso removido"})
+}
+
+// planOwner retorna de quem são os planos da requisição: o próprio usuário
+// ou, nas rotas /clients/:client_id, o cliente que deu acesso a ele
+func planOwner(c *gin.Context) (uint, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return 0, false
+	}
+	if c.Param("client_id") == "" {
+		return userID.(uint), true
+	}
+
+	clientID, err := strconv.ParseUint(c.Param("client_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Paciente não encontrado"})
+		return 0, false
+	}
+	allowed, err := models.HasNutritionistAccess(database.DB, userID.(uint), uint(clientID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar acesso"})
+		return 0, false
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Sem acesso aos dados deste paciente"})
+		return 0, false
+	}
+	return uint(clientID), true
+}
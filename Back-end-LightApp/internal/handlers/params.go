@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/juliapinheiro42/LightApp/internal/models"
+)
+
+// parseDateIn lê uma data AAAA-MM-DD no fuso informado (em geral o do
+// usuário); vazio retorna hoje nesse fuso
+func parseDateIn(value string, loc *time.Location) (time.Time, error) {
+	if value == "" {
+		now := time.Now().In(loc)
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc), nil
+	}
+	return time.ParseInLocation(models.DateLayout, value, loc)
+}
+
+// parseDateRange lê ?from= e ?to= (AAAA-MM-DD, inclusive) no fuso do usuário.
+// Sem to, o período termina hoje; sem from, cobre defaultDays dias até to.
+// O período é limitado a models.MaxSummaryDays.
+func parseDateRange(c *gin.Context, loc *time.Location, defaultDays int) (time.Time, time.Time, error) {
+	now := time.Now().In(loc)
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.ParseInLocation(models.DateLayout, value, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("Data final inválida, use o formato AAAA-MM-DD")
+		}
+		to = parsed
+	}
+	from := to.AddDate(0, 0, 1-defaultDays)
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.ParseInLocation(models.DateLayout, value, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("Data inicial inválida, use o formato AAAA-MM-DD")
+		}
+		from = parsed
+	}
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, errors.New("A data inicial deve ser anterior à data final")
+	}
+	if from.AddDate(0, 0, models.MaxSummaryDays-1).Before(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("Período máximo de %d dias", models.MaxSummaryDays)
+	}
+	return from, to, nil
+}
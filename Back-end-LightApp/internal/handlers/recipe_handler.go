@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juliapinheiro42/LightApp/database"
+	"github.com/juliapinheiro42/LightApp/internal/models"
+	"gorm.io/gorm"
+)
+
+// recipeView é a receita com os nutrientes totais e por porção
+type recipeView struct {
+	models.Recipe
+	Totals     models.Nutrients `json:"totals"`
+	PerServing models.Nutrients `json:"per_serving"`
+}
+
+func newRecipeView(recipe models.Recipe) recipeView {
+	return recipeView{Recipe: recipe, Totals: recipe.Totals(), PerServing: recipe.PerServing()}
+}
+
+func CreateRecipe(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	var recipe models.Recipe
+	if err := c.ShouldBindJSON(&recipe); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	recipe.ID = 0
+	recipe.UserID = userID.(uint)
+	if err := recipe.Prepare(database.DB); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := database.DB.Create(&recipe).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar receita"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, newRecipeView(recipe))
+}
+
+func ListRecipes(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	var recipes []models.Recipe
+	if err := database.DB.Preload("Items").Where("user_id = ?", userID).Order("name").Find(&recipes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar receitas"})
+		return
+	}
+
+	views := make([]recipeView, 0, len(recipes))
+	for _, recipe := range recipes {
+		views = append(views, newRecipeView(recipe))
+	}
+	c.JSON(http.StatusOK, gin.H{"recipes": views})
+}
+
+func GetRecipe(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	recipe, err := findRecipe(userID.(uint), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Receita não encontrada"})
+		return
+	}
+
+	c.JSON(http.StatusOK, newRecipeView(recipe))
+}
+
+// UpdateRecipe substitui nome, rendimento, modo de preparo e ingredientes
+func UpdateRecipe(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	recipe, err := findRecipe(userID.(uint), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Receita não encontrada"})
+		return
+	}
+
+	var update models.Recipe
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	update.ID, update.UserID, update.CreatedAt = recipe.ID, recipe.UserID, recipe.CreatedAt
+	if err := update.Prepare(database.DB); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("recipe_id = ?", recipe.ID).Delete(&models.RecipeItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Save(&update).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar receita"})
+		return
+	}
+
+	c.JSON(http.StatusOK, newRecipeView(update))
+}
+
+func DeleteRecipe(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	result := database.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).Delete(&models.Recipe{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao remover receita"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Receita não encontrada"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Receita removida"})
+}
+
+func findRecipe(userID uint, recipeID string) (models.Recipe, error) {
+	var recipe models.Recipe
+	err := database.DB.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).Where("id = ? AND user_id = ?", recipeID, userID).First(&recipe).Error
+	return recipe, err
+}
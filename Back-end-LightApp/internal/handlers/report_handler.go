@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juliapinheiro42/LightApp/database"
+	"github.com/juliapinheiro42/LightApp/internal/dri"
+	"github.com/juliapinheiro42/LightApp/internal/models"
+	"github.com/juliapinheiro42/LightApp/internal/report"
+)
+
+// Quantidade de alimentos listados no relatório em PDF
+const reportTopFoods = 15
+
+// GetNutritionReportPDF gera o relatório em PDF do período (?from=&to=,
+// padrão: últimos 30 dias) para compartilhar com o nutricionista
+func GetNutritionReportPDF(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+		return
+	}
+	if err := user.ApplyEnergyData(database.DB); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar dados de gasto energético"})
+		return
+	}
+
+	loc := user.Location()
+	from, to, err := parseDateRange(c, loc, 30)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	totals, err := models.SummaryTotals(database.DB, user.ID, from.Format(models.DateLayout), to.Format(models.DateLayout))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao calcular resumo"})
+		return
+	}
+	days := models.BuildSummaryBuckets(totals, from, to, models.SummaryGroupDay)
+
+	foods, err := models.TopFoodsByEnergy(database.DB, user.ID, from, to.AddDate(0, 0, 1), reportTopFoods)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar alimentos"})
+		return
+	}
+
+	data := report.NutritionReport{
+		User:        user,
+		From:        from,
+		To:          to,
+		GeneratedAt: time.Now().In(loc),
+		Days:        days,
+		TopFoods:    foods,
+	}
+
+	// Seções opcionais ficam de fora quando o perfil está incompleto
+	if imc, status, err := user.IMC(); err == nil {
+		data.IMC, data.IMCStatus = imc, status
+	}
+	if targets, err := user.NutritionTargets(); err == nil {
+		data.Targets = &targets
+	}
+
+	table, err := dri.Load()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao carregar valores de referência"})
+		return
+	}
+	data.ReferenceVersion = table.Version
+	if user.Age != 0 && user.Sex != "" {
+		period := models.MergeSummaryBuckets(days)
+		assessments, err := table.Assess(period.Average.Micronutrients.Values(), user.Age, user.Sex)
+		if err != nil && !errors.Is(err, dri.ErrNoReference) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao avaliar ingestão"})
+			return
+		}
+		data.Micronutrients = assessments
+	}
+
+	var buf bytes.Buffer
+	if err := report.WritePDF(&buf, data); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar relatório"})
+		return
+	}
+
+	filename := fmt.Sprintf("relatorio-%s-%s.pdf", from.Format(models.DateLayout), to.Format(models.DateLayout))
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Data(http.StatusOK, "application/pdf", buf.Bytes())
+}
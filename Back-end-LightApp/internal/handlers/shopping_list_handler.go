@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juliapinheiro42/LightApp/database"
+	"github.com/juliapinheiro42/LightApp/internal/models"
+	"gorm.io/gorm"
+)
+
+// Maior período aceito para gerar uma lista de compras
+const maxShoppingListDays = 31
+
+// CreateShoppingList gera a lista de compras a partir de um plano alimentar
+// (no período from–to, padrão: 7 dias), de receitas (com as porções
+// desejadas) ou dos dois juntos
+func CreateShoppingList(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	var request struct {
+		PlanID  *uint  `json:"plan_id"`
+		Name    string `json:"name"`
+		From    string `json:"from"`
+		To      string `json:"to"`
+		Recipes []struct {
+			RecipeID uint    `json:"recipe_id"`
+			Servings float64 `json:"servings"` // Zero: o rendimento da receita
+		} `json:"recipes"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if request.PlanID == nil && len(request.Recipes) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Informe o plano alimentar ou as receitas"})
+		return
+	}
+
+	list := models.ShoppingList{UserID: userID.(uint), Name: strings.TrimSpace(request.Name), RecipeIDs: []uint{}}
+	gramsByFood := make(map[uint]float64)
+	var names []string
+
+	if request.PlanID != nil {
+		loc, err := models.UserLocation(database.DB, userID.(uint))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+			return
+		}
+
+		// Período no fuso do usuário, como o plano
+		from, err := parseDateIn(request.From, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Data inicial inválida, use o formato AAAA-MM-DD"})
+			return
+		}
+		to := from.AddDate(0, 0, 6)
+		if request.To != "" {
+			if to, err = parseDateIn(request.To, loc); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Data final inválida, use o formato AAAA-MM-DD"})
+				return
+			}
+		}
+		if to.Before(from) || to.Sub(from).Hours()/24 >= maxShoppingListDays {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Período inválido (máximo de %d dias)", maxShoppingListDays)})
+			return
+		}
+
+		plan, err := findMealPlan(userID.(uint), fmt.Sprint(*request.PlanID))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Plano alimentar não encontrado"})
+			return
+		}
+		models.AddPlanIngredients(gramsByFood, plan, from, to)
+		list.PlanID, list.FromDate, list.ToDate = &plan.ID, &from, &to
+		names = append(names, plan.Name)
+	}
+
+	for _, requested := range request.Recipes {
+		if requested.Servings < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Número de porções inválido"})
+			return
+		}
+		recipe, err := findRecipe(userID.(uint), fmt.Sprint(requested.RecipeID))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Receita não encontrada"})
+			return
+		}
+		models.AddRecipeIngredients(gramsByFood, recipe, requested.Servings)
+		list.RecipeIDs = append(list.RecipeIDs, recipe.ID)
+		names = append(names, recipe.Name)
+	}
+
+	items, err := models.BuildShoppingListItems(database.DB, gramsByFood)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar lista de compras"})
+		return
+	}
+	list.Items = items
+	if list.Name == "" {
+		list.Name = strings.Join(names, ", ")
+	}
+
+	if err := database.DB.Create(&list).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao salvar lista de compras"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, list)
+}
+
+func ListShoppingLists(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	var lists []models.ShoppingList
+	if err := database.DB.Where("user_id = ?", userID).Order("created_at DESC").Find(&lists).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar listas de compras"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"lists": lists})
+}
+
+func GetShoppingList(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	list, err := findShoppingList(userID.(uint), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Lista de compras não encontrada"})
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// ExportShoppingList devolve a lista em texto simples para compartilhar
+func ExportShoppingList(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	list, err := findShoppingList(userID.(uint), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Lista de compras não encontrada"})
+		return
+	}
+
+	loc, err := models.UserLocation(database.DB, userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+		return
+	}
+
+	c.String(http.StatusOK, list.Text(loc))
+}
+
+// CheckShoppingListItem marca ou desmarca um item como comprado
+func CheckShoppingListItem(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	var request struct {
+		Checked bool `json:"checked"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	list, err := findShoppingList(userID.(uint), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Lista de compras não encontrada"})
+		return
+	}
+
+	result := database.DB.Model(&models.ShoppingListItem{}).
+		Where("id = ? AND list_id = ?", c.Param("item_id"), list.ID).
+		Update("checked", request.Checked)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar item"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Item não encontrado na lista"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Item atualizado"})
+}
+
+func DeleteShoppingList(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	result := database.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).Delete(&models.ShoppingList{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao remover lista de compras"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Lista de compras não encontrada"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Lista de compras removida"})
+}
+
+func findShoppingList(userID uint, listID string) (models.ShoppingList, error) {
+	var list models.ShoppingList
+	err := database.DB.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).Where("id = ? AND user_id = ?", listID, userID).First(&list).Error
+	return list, err
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juliapinheiro42/LightApp/database"
+	"github.com/juliapinheiro42/LightApp/internal/models"
+)
+
+// GetNutritionSummary resume a ingestão entre from e to (AAAA-MM-DD, inclusive)
+// agrupada por dia, semana ou mês (?group=). Sem datas, retorna os últimos 7 dias.
+func GetNutritionSummary(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	group := c.DefaultQuery("group", models.SummaryGroupDay)
+	if !models.IsValidSummaryGroup(group) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Agrupamento inválido (use day, week ou month)"})
+		return
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+		return
+	}
+	loc := user.Location()
+
+	from, to, err := parseDateRange(c, loc, 7)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	totals, err := models.SummaryTotals(database.DB, user.ID, from.Format(models.DateLayout), to.Format(models.DateLayout))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao calcular resumo"})
+		return
+	}
+
+	buckets := models.BuildSummaryBuckets(totals, from, to, group)
+
+	c.JSON(http.StatusOK, gin.H{
+		"from":      from.Format(models.DateLayout),
+		"to":        to.Format(models.DateLayout),
+		"group":     group,
+		"time_zone": loc.String(),
+		"period":    models.MergeSummaryBuckets(buckets),
+		"buckets":   buckets,
+	})
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juliapinheiro42/LightApp/database"
+	"github.com/juliapinheiro42/LightApp/internal/models"
+)
+
+// GetTargets retorna a configuração de metas, as metas em gramas e os presets
+func GetTargets(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+		return
+	}
+	if err := user.ApplyEnergyData(database.DB); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar dados de gasto energético"})
+		return
+	}
+
+	response := gin.H{
+		"macro_targets": user.EffectiveMacroTargets(),
+		"custom":        user.MacroTargets != nil,
+		"presets":       models.MacroPresets,
+	}
+	// Sem perfil completo a configuração existe, mas as metas não podem ser calculadas
+	if targets, err := user.NutritionTargets(); err != nil {
+		response["targets"] = nil
+		response["targets_error"] = err.Error()
+	} else {
+		response["targets"] = targets
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateTargets grava a configuração de metas. Com "preset", os macros vêm do
+// perfil escolhido; sem ele, são usados os macros enviados. A configuração só
+// é aceita se os macros fecharem a meta calórica do usuário.
+func UpdateTargets(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	var config models.MacroTargets
+	if err := c.ShouldBindJSON(&config); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if config.Preset != "" {
+		if err := config.ApplyPreset(config.Preset); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+		return
+	}
+	if err := user.ApplyEnergyData(database.DB); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar dados de gasto energético"})
+		return
+	}
+
+	targets, err := user.NutritionTargetsWith(config)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user.MacroTargets = &config
+	if err := database.DB.Model(&user).Select("macro_targets").Updates(&user).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao salvar metas"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"macro_targets": config,
+		"targets":       targets,
+	})
+}
+
+// ResetTargets volta às metas padrão do objetivo
+func ResetTargets(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	if err := database.DB.Model(&models.User{}).Where("id = ?", userID).Update("macro_targets", nil).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao remover metas"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Metas padrão restauradas"})
+}
@@ -1,11 +1,15 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/juliapinheiro42/LightApp/database"
 	"github.com/juliapinheiro42/LightApp/internal/models"
+	"gorm.io/gorm"
 )
 
 func CalculateIMC(c *gin.Context) {
@@ -21,33 +25,28 @@ func CalculateIMC(c *gin.Context) {
 		return
 	}
 
-	if user.Weight == 0 || user.Height == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Peso e altura precisam ser cadastrados"})
+	latest, err := user.ApplyLatestWeight(database.DB)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar pesagens"})
 		return
 	}
 
-	heightInMeters := user.Height / 100.0
-	imc := user.Weight / (heightInMeters * heightInMeters)
-
-	var status string
-	switch {
-	case imc < 18.5:
-		status = "Abaixo do peso"
-	case imc >= 18.5 && imc < 24.9:
-		status = "Peso normal"
-	case imc >= 25 && imc < 29.9:
-		status = "Sobrepeso"
-	case imc >= 30:
-		status = "Obesidade"
+	imc, status, err := user.IMC()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"imc":    imc,
-		"status": status,
+		"imc":        imc,
+		"status":     status,
+		"weight":     user.Weight,
+		"weighed_at": weighedAt(latest),
 	})
 }
 
-// Cálculo da recomendação calórica
+// Cálculo da recomendação calórica com a equação do perfil ou a informada em
+// ?equation=, para comparar. A resposta indica a equação e os dados usados.
 func CalculateCalories(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
@@ -61,37 +60,110 @@ func CalculateCalories(c *gin.Context) {
 		return
 	}
 
-	if user.Weight == 0 || user.Height == 0 || user.Age == 0 || user.Gender == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Peso, altura, idade e gênero precisam ser cadastrados"})
+	latest, err := user.ApplyLatestWeight(database.DB)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar pesagens"})
+		return
+	}
+
+	if err := user.ApplyEnergyData(database.DB); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar dados de gasto energético"})
 		return
 	}
 
-	var tmb float64
-	if user.Gender == "male" {
-		tmb = 88.36 + (13.4 * user.Weight) + (4.8 * user.Height) - (5.7 * float64(user.Age))
+	var energy models.EnergyEstimate
+	if equation := c.Query("equation"); equation != "" {
+		energy, err = user.EstimateEnergyWith(equation)
 	} else {
-		tmb = 447.6 + (9.2 * user.Weight) + (3.1 * user.Height) - (4.3 * float64(user.Age))
+		energy, err = user.EstimateEnergy()
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"TMB":           energy.TMB,
+		"TDEE":          energy.TDEE,
+		"tdee_source":   energy.TDEESource,
+		"formula_tdee":  energy.FormulaTDEE,
+		"goal_calories": energy.GoalCalories,
+		"equation":      energy.Equation,
+		"equation_name": energy.EquationName,
+		"inputs":        energy.Inputs,
+		"goal":          user.Goal,
+		"weight":        user.Weight,
+		"weighed_at":    weighedAt(latest),
+	})
+}
+
+// GetAdaptiveTDEE estima o gasto real pelo balanço energético dos últimos
+// ?days= dias (padrão: 28), comparando com o gasto da fórmula do perfil
+func GetAdaptiveTDEE(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
 	}
 
-	tdee := tmb * user.ActivityLevel
+	days, err := strconv.Atoi(c.DefaultQuery("days", strconv.Itoa(models.AdaptiveTDEEWindowDays)))
+	if err != nil || days < models.AdaptiveTDEEMinDays || days > models.AdaptiveTDEEMaxDays {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Período inválido (entre 14 e 90 dias)"})
+		return
+	}
 
-	var goalCalories float64
-	switch user.Goal {
-	case "lose":
-		goalCalories = tdee * 0.8
-	case "gain":
-		goalCalories = tdee * 1.15
-	default:
-		goalCalories = tdee
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+		return
 	}
 
+	estimate, err := models.EstimateAdaptiveTDEE(database.DB, user, days)
+	if errors.Is(err, models.ErrInsufficientAdaptiveData) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "estimate": estimate})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao estimar gasto energético"})
+		return
+	}
+
+	response := gin.H{
+		"estimate":          estimate,
+		"use_adaptive_tdee": user.UseAdaptiveTDEE,
+		// Com confiança baixa, as metas continuam usando a fórmula
+		"used_for_goals": user.UseAdaptiveTDEE && estimate.Confidence != models.ConfidenceLow,
+		"formula_tdee":   nil,
+	}
+	if err := user.ApplyBodyFat(database.DB); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar dados de gasto energético"})
+		return
+	}
+	if energy, err := user.EstimateEnergy(); err == nil {
+		response["formula_tdee"] = energy.FormulaTDEE
+		response["difference"] = estimate.TDEE - energy.FormulaTDEE
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetEnergyOptions lista as equações de TMB e os níveis de atividade aceitos
+func GetEnergyOptions(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
-		"TMB":           tmb,
-		"TDEE":          tdee,
-		"goal_calories": goalCalories,
-		"goal":          user.Goal,
+		"equations":         models.EnergyEquationList(),
+		"activity_presets":  models.ActivityPresets,
+		"default_equation":  models.EquationHarrisBenedict,
+		"body_fat_equation": models.EquationKatchMcArdle,
 	})
 }
+
+// weighedAt retorna a data da pesagem usada (nil quando vem do cadastro)
+func weighedAt(entry *models.WeightEntry) *time.Time {
+	if entry == nil {
+		return nil
+	}
+	return &entry.MeasuredAt
+}
+
 func UpdateUser(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
@@ -105,20 +177,104 @@ func UpdateUser(c *gin.Context) {
 		return
 	}
 
-	var updateData models.User
-	if err := c.ShouldBindJSON(&updateData); err != nil {
+	var request struct {
+		Weight          *float64 `json:"weight"`
+		Height          *float64 `json:"height"`
+		Age             *int     `json:"age"`
+		Gender          *string  `json:"gender"`
+		Sex             *string  `json:"sex"`
+		Activity        *string  `json:"activity"`
+		ActivityLevel   *float64 `json:"activity_level"`
+		EnergyEquation  *string  `json:"energy_equation"`
+		UseAdaptiveTDEE *bool    `json:"use_adaptive_tdee"`
+		Goal            *string  `json:"goal"`
+		TimeZone        *string  `json:"time_zone"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	user.Weight = updateData.Weight
-	user.Height = updateData.Height
-	user.Age = updateData.Age
-	user.Gender = updateData.Gender
-	user.ActivityLevel = updateData.ActivityLevel
-	user.Goal = updateData.Goal
+	// Apenas os campos enviados são alterados.
+	// Um peso novo vira uma pesagem no histórico; zero mantém a última pesagem
+	var weighing *models.WeightEntry
+	if request.Weight != nil && *request.Weight != 0 && *request.Weight != user.Weight {
+		if err := models.ValidateWeight(*request.Weight); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		weighing = &models.WeightEntry{UserID: user.ID, Weight: *request.Weight, MeasuredAt: time.Now()}
+		user.Weight = *request.Weight
+	}
+	if request.Height != nil {
+		user.Height = *request.Height
+	}
+	if request.Age != nil {
+		user.Age = *request.Age
+	}
+	if request.Gender != nil {
+		user.Gender = *request.Gender
+	}
+	if request.Sex != nil {
+		user.Sex = *request.Sex
+	}
+	// Só o fator enviado: o nível passa a ser o do fator
+	if request.ActivityLevel != nil {
+		user.Activity, user.ActivityLevel = "", *request.ActivityLevel
+	}
+	if request.Activity != nil {
+		user.Activity = *request.Activity
+	}
+	if request.EnergyEquation != nil {
+		user.EnergyEquation = *request.EnergyEquation
+	}
+	if request.UseAdaptiveTDEE != nil {
+		user.UseAdaptiveTDEE = *request.UseAdaptiveTDEE
+	}
+	if request.Goal != nil {
+		user.Goal = *request.Goal
+	}
+	// Perfis antigos com fator fora dos presets só são validados quando o
+	// usuário altera os dados do gasto energético
+	energyChanged := request.Gender != nil || request.Sex != nil || request.Activity != nil ||
+		request.ActivityLevel != nil || request.EnergyEquation != nil
+	if energyChanged {
+		if err := user.NormalizeEnergyProfile(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	timeZoneChanged := false
+	if request.TimeZone != nil && *request.TimeZone != "" {
+		if _, err := time.LoadLocation(*request.TimeZone); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Fuso horário inválido"})
+			return
+		}
+		timeZoneChanged = *request.TimeZone != user.TimeZone
+		user.TimeZone = *request.TimeZone
+	}
 
-	database.DB.Save(&user)
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&user).Error; err != nil {
+			return err
+		}
+		if weighing != nil {
+			if err := tx.Create(weighing).Error; err != nil {
+				return err
+			}
+		}
+		// Os totais diários dependem do fuso: com outro fuso, os dias mudam
+		if timeZoneChanged {
+			_, err := models.RebuildDailySummaries(tx, []uint{user.ID}, "", "")
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar dados"})
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Dados atualizados com sucesso"})
 }
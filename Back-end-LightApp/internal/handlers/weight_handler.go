@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juliapinheiro42/LightApp/database"
+	"github.com/juliapinheiro42/LightApp/internal/models"
+	"gorm.io/gorm"
+)
+
+// Dias de pesagens anteriores ao período usados para estabilizar a tendência
+const weightTrendWarmupDays = 60
+
+type weightRequest struct {
+	Weight     float64    `json:"weight"`
+	MeasuredAt *time.Time `json:"measured_at"`
+	Note       string     `json:"note"`
+}
+
+func (r weightRequest) validate() string {
+	if err := models.ValidateWeight(r.Weight); err != nil {
+		return err.Error()
+	}
+	if r.MeasuredAt != nil && r.MeasuredAt.After(time.Now()) {
+		return "A pesagem não pode estar no futuro"
+	}
+	if len(r.Note) > models.MaxMealNoteLength {
+		return "Anotação muito longa"
+	}
+	return ""
+}
+
+// saveWeight grava a pesagem e atualiza o peso do perfil na mesma transação
+func saveWeight(entry *models.WeightEntry) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(entry).Error; err != nil {
+			return err
+		}
+		return models.SyncUserWeight(tx, entry.UserID)
+	})
+}
+
+func LogWeight(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	var request weightRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if msg := request.validate(); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	entry := models.WeightEntry{
+		UserID:     userID.(uint),
+		Weight:     request.Weight,
+		MeasuredAt: time.Now(),
+		Note:       strings.TrimSpace(request.Note),
+	}
+	if request.MeasuredAt != nil {
+		entry.MeasuredAt = *request.MeasuredAt
+	}
+
+	if err := saveWeight(&entry); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao registrar pesagem"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+func UpdateWeight(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	var entry models.WeightEntry
+	if err := database.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&entry).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pesagem não encontrada"})
+		return
+	}
+
+	var request weightRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if msg := request.validate(); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	entry.Weight = request.Weight
+	entry.Note = strings.TrimSpace(request.Note)
+	if request.MeasuredAt != nil {
+		entry.MeasuredAt = *request.MeasuredAt
+	}
+
+	if err := saveWeight(&entry); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar pesagem"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+func DeleteWeight(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	var deleted int64
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND user_id = ?", c.Param("id"), userID).Delete(&models.WeightEntry{})
+		if result.Error != nil {
+			return result.Error
+		}
+		deleted = result.RowsAffected
+		return models.SyncUserWeight(tx, userID.(uint))
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao remover pesagem"})
+		return
+	}
+	if deleted == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pesagem não encontrada"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Pesagem removida"})
+}
+
+// GetWeightHistory retorna as pesagens do período (?from=&to=, padrão: últimos
+// 90 dias) e a tendência suavizada por média móvel exponencial
+func GetWeightHistory(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+		return
+	}
+	loc := user.Location()
+
+	from, to, err := parseDateRange(c, loc, 90)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var entries []models.WeightEntry
+	if err := database.DB.Where("user_id = ? AND measured_at >= ? AND measured_at < ?",
+		user.ID, from.AddDate(0, 0, -weightTrendWarmupDays), to.AddDate(0, 0, 1)).
+		Order("measured_at, id").Find(&entries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar pesagens"})
+		return
+	}
+
+	// A tendência usa as pesagens anteriores; a resposta mostra só o período
+	fromDate := from.Format(models.DateLayout)
+	trend := []models.WeightTrendPoint{}
+	for _, point := range models.WeightTrend(entries, loc) {
+		if point.Date >= fromDate {
+			trend = append(trend, point)
+		}
+	}
+	inRange := []models.WeightEntry{}
+	for _, entry := range entries {
+		if !entry.MeasuredAt.Before(from) {
+			inRange = append(inRange, entry)
+		}
+	}
+
+	response := gin.H{
+		"from":      fromDate,
+		"to":        to.Format(models.DateLayout),
+		"time_zone": loc.String(),
+		"entries":   inRange,
+		"trend":     trend,
+	}
+	if len(trend) >= 2 {
+		first, last := trend[0], trend[len(trend)-1]
+		start, _ := time.Parse(models.DateLayout, first.Date)
+		end, _ := time.Parse(models.DateLayout, last.Date)
+		change := last.Trend - first.Trend
+		response["trend_change"] = change
+		response["weekly_rate"] = change / (end.Sub(start).Hours() / 24) * 7
+	}
+
+	c.JSON(http.StatusOK, response)
+}
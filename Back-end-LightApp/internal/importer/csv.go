@@ -0,0 +1,283 @@
+// Package importer lê diários exportados por outros aplicativos (MyFitnessPal,
+// FatSecret e CSVs parecidos) e os converte em refeições do LightApp
+package importer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juliapinheiro42/LightApp/internal/mealparser"
+	"github.com/juliapinheiro42/LightApp/internal/models"
+)
+
+// Origens reconhecidas pelo cabeçalho do CSV
+const (
+	SourceMyFitnessPal = "myfitnesspal"
+	SourceFatSecret    = "fatsecret"
+	SourceGeneric      = "generic"
+)
+
+// Ordem de dia e mês nas datas com barras (02/01/2024)
+const (
+	DateOrderDMY = "dmy"
+	DateOrderMDY = "mdy"
+)
+
+// Limite de linhas por arquivo, suficiente para anos de histórico
+const maxRows = 200000
+
+// Entry é uma linha do CSV: um alimento ou, no MyFitnessPal, o total de uma refeição
+type Entry struct {
+	Line     int
+	Date     string // AAAA-MM-DD
+	Time     string // HH:MM, vazio quando o arquivo não informa
+	MealType string
+	Food     string // Vazio quando a linha é o total da refeição
+	Quantity float64
+	Unit     string
+	models.Nutrients
+}
+
+// Warning aponta uma linha ignorada ou ajustada
+type Warning struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// Colunas reconhecidas, pelos nomes normalizados (minúsculas, sem acentos e
+// sem a unidade entre parênteses) usados por cada aplicativo
+var columnAliases = map[string][]string{
+	"date":      {"date", "data", "day", "dia"},
+	"time":      {"time", "hora", "horario"},
+	"meal":      {"meal", "meal type", "refeicao", "tipo de refeicao"},
+	"food":      {"food", "food name", "name", "description", "item", "alimento", "descricao"},
+	"quantity":  {"quantity", "amount", "serving", "serving size", "quantidade", "porcao"},
+	"calories":  {"calories", "energy", "kcal", "cals", "calorias", "energia"},
+	"fat":       {"fat", "total fat", "gordura", "gorduras", "gordura total"},
+	"carbs":     {"carbohydrates", "carbohydrate", "carbs", "carbs total", "carboidratos", "carboidrato"},
+	"protein":   {"protein", "proteina", "proteinas"},
+	"fiber":     {"fiber", "fibre", "dietary fiber", "fibra", "fibras", "fibra alimentar"},
+	"sodium":    {"sodium", "sodio"},
+	"potassium": {"potassium", "potassio"},
+}
+
+// Nomes de refeição dos outros aplicativos, normalizados
+var mealAliases = map[string]string{
+	"breakfast": models.MealTypeBreakfast, "cafe da manha": models.MealTypeBreakfast, "desjejum": models.MealTypeBreakfast,
+	"lunch": models.MealTypeLunch, "almoco": models.MealTypeLunch,
+	"dinner": models.MealTypeDinner, "jantar": models.MealTypeDinner, "supper": models.MealTypeDinner, "ceia": models.MealTypeDinner,
+	"snack": models.MealTypeSnack, "snacks": models.MealTypeSnack, "lanche": models.MealTypeSnack, "lanches": models.MealTypeSnack,
+	"other": models.MealTypeSnack, "outros": models.MealTypeSnack,
+}
+
+var unitSuffix = regexp.MustCompile(`\s*\(.*\)\s*$`)
+
+// Parse lê o CSV e retorna as linhas reconhecidas, a origem detectada e os
+// avisos das linhas ignoradas. O separador (vírgula ou ponto e vírgula) é
+// detectado pelo cabeçalho.
+func Parse(r io.Reader, dateOrder string) ([]Entry, string, []Warning, error) {
+	reader := bufio.NewReader(r)
+	first, err := reader.Peek(4096)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, "", nil, err
+	}
+	headerLine, _, _ := strings.Cut(strings.TrimPrefix(string(first), "\ufeff"), "\n")
+	if strings.HasPrefix(string(first), "\ufeff") {
+		reader.Discard(len("\ufeff"))
+	}
+
+	records := csv.NewReader(reader)
+	if strings.Count(headerLine, ";") > strings.Count(headerLine, ",") {
+		records.Comma = ';'
+	}
+	records.FieldsPerRecord = -1
+	records.LazyQuotes = true
+
+	header, err := records.Read()
+	if err != nil {
+		return nil, "", nil, errors.New("Arquivo CSV vazio ou inválido")
+	}
+	columns, raw := mapColumns(header)
+	if _, ok := columns["date"]; !ok {
+		return nil, "", nil, errors.New("Coluna de data não encontrada no CSV")
+	}
+	if _, ok := columns["calories"]; !ok {
+		return nil, "", nil, errors.New("Coluna de calorias não encontrada no CSV")
+	}
+	source := detectSource(columns, raw)
+
+	var entries []Entry
+	var warnings []Warning
+	for line := 2; ; line++ {
+		record, err := records.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			warnings = append(warnings, Warning{Line: line, Message: "Linha inválida no CSV"})
+			continue
+		}
+		if line > maxRows+1 {
+			return nil, "", nil, fmt.Errorf("O arquivo tem mais de %d linhas", maxRows)
+		}
+
+		entry, err := parseRecord(record, columns, dateOrder)
+		if err != nil {
+			warnings = append(warnings, Warning{Line: line, Message: err.Error()})
+			continue
+		}
+		entry.Line = line
+		entries = append(entries, entry)
+	}
+	return entries, source, warnings, nil
+}
+
+// mapColumns associa cada coluna conhecida ao seu índice no cabeçalho
+func mapColumns(header []string) (map[string]int, map[string]bool) {
+	raw := map[string]bool{}
+	columns := map[string]int{}
+	for i, name := range header {
+		normalized := mealparser.Normalize(unitSuffix.ReplaceAllString(name, ""))
+		raw[normalized] = true
+		for column, aliases := range columnAliases {
+			if _, taken := columns[column]; taken {
+				continue
+			}
+			for _, alias := range aliases {
+				if normalized == alias {
+					columns[column] = i
+				}
+			}
+		}
+	}
+	return columns, raw
+}
+
+// detectSource identifica o aplicativo: o MyFitnessPal exporta totais por
+// refeição com as gorduras detalhadas; o FatSecret, um alimento por linha
+func detectSource(columns map[string]int, raw map[string]bool) string {
+	_, hasFood := columns["food"]
+	switch {
+	case !hasFood && raw["saturated fat"] && raw["polyunsaturated fat"]:
+		return SourceMyFitnessPal
+	case hasFood && (raw["sat fat"] || raw["saturated fat"]):
+		return SourceFatSecret
+	}
+	return SourceGeneric
+}
+
+func parseRecord(record []string, columns map[string]int, dateOrder string) (Entry, error) {
+	value := func(column string) string {
+		index, ok := columns[column]
+		if !ok || index >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[index])
+	}
+
+	var entry Entry
+	date, err := parseDate(value("date"), dateOrder)
+	if err != nil {
+		return entry, err
+	}
+	entry.Date = date.Format(models.DateLayout)
+
+	if clock := value("time"); clock != "" {
+		parsed, err := time.Parse("15:04", clock)
+		if err != nil {
+			parsed, err = time.Parse("3:04 PM", strings.ToUpper(clock))
+		}
+		if err == nil {
+			entry.Time = parsed.Format("15:04")
+		}
+	}
+
+	entry.MealType = models.MealTypeSnack
+	if mealType, ok := mealAliases[mealparser.Normalize(value("meal"))]; ok {
+		entry.MealType = mealType
+	}
+
+	entry.Food = value("food")
+	entry.Quantity, entry.Unit = parseQuantity(value("quantity"))
+
+	numbers := map[string]*float64{
+		"calories": &entry.Calories, "protein": &entry.Protein, "carbs": &entry.Carbs, "fat": &entry.Fat,
+		"fiber": &entry.Fiber, "sodium": &entry.Sodium, "potassium": &entry.Potassium,
+	}
+	for column, target := range numbers {
+		text := value(column)
+		if text == "" {
+			continue
+		}
+		number, err := parseNumber(text)
+		if err != nil || number < 0 {
+			return entry, fmt.Errorf("Valor inválido na coluna %s: %q", column, text)
+		}
+		*target = number
+	}
+	if entry.Calories <= 0 {
+		return entry, errors.New("Linha sem calorias")
+	}
+	return entry, nil
+}
+
+var dateLayouts = map[string][]string{
+	DateOrderDMY: {"02/01/2006", "2/1/2006", "02-01-2006", "02.01.2006"},
+	DateOrderMDY: {"01/02/2006", "1/2/2006", "01-02-2006"},
+}
+
+func parseDate(text, dateOrder string) (time.Time, error) {
+	text, _, _ = strings.Cut(text, " ") // Alguns arquivos trazem a hora junto da data
+	layouts := append([]string{models.DateLayout, "2006/01/02"}, dateLayouts[dateOrder]...)
+	for _, layout := range layouts {
+		if date, err := time.Parse(layout, text); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("Data inválida: %q", text)
+}
+
+// parseNumber aceita "1234.5", "1,234.5", "1.234,5" e "12,5"
+func parseNumber(text string) (float64, error) {
+	text = strings.ReplaceAll(text, " ", "")
+	comma, dot := strings.LastIndex(text, ","), strings.LastIndex(text, ".")
+	switch {
+	case comma >= 0 && dot >= 0 && comma > dot:
+		text = strings.ReplaceAll(strings.ReplaceAll(text, ".", ""), ",", ".")
+	case comma >= 0 && dot >= 0:
+		text = strings.ReplaceAll(text, ",", "")
+	case comma >= 0:
+		text = strings.ReplaceAll(text, ",", ".")
+	}
+	return strconv.ParseFloat(text, 64)
+}
+
+// parseQuantity separa "150 g" ou "1 cup" em número e unidade
+func parseQuantity(text string) (float64, string) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return 0, ""
+	}
+	number, err := parseNumber(fields[0])
+	if err != nil {
+		// "150g": número e unidade juntos
+		split := strings.IndexFunc(fields[0], func(r rune) bool { return (r < '0' || r > '9') && r != '.' && r != ',' })
+		if split <= 0 {
+			return 0, text
+		}
+		number, err = parseNumber(fields[0][:split])
+		if err != nil {
+			return 0, text
+		}
+		fields = append([]string{fields[0][split:]}, fields[1:]...)
+		return number, strings.Join(fields, " ")
+	}
+	return number, strings.Join(fields[1:], " ")
+}
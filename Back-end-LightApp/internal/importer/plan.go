@@ -0,0 +1,211 @@
+package importer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juliapinheiro42/LightApp/internal/mealparser"
+	"github.com/juliapinheiro42/LightApp/internal/models"
+	"gorm.io/gorm"
+)
+
+// Semelhança mínima para associar o nome do alimento a um item da TACO
+const minMatchScore = 0.9
+
+// Horário usado quando o arquivo traz só a data. Horários fixos também tornam
+// a importação repetível: a mesma refeição cai sempre no mesmo instante.
+var defaultMealTimes = map[string]string{
+	models.MealTypeBreakfast: "08:00",
+	models.MealTypeLunch:     "12:30",
+	models.MealTypeSnack:     "16:00",
+	models.MealTypeDinner:    "19:30",
+}
+
+// Rótulo dos totais de refeição, quando o arquivo não lista os alimentos
+var sourceLabels = map[string]string{
+	SourceMyFitnessPal: "MyFitnessPal",
+	SourceFatSecret:    "FatSecret",
+	SourceGeneric:      "importação",
+}
+
+// PlannedMeal é uma refeição que será criada
+type PlannedMeal struct {
+	Date     string            `json:"date"`
+	MealType string            `json:"meal_type"`
+	At       time.Time         `json:"created_at"`
+	Items    []models.MealItem `json:"items"`
+}
+
+// Plan é o resultado da análise do arquivo: o que será gravado e o que foi ignorado
+type Plan struct {
+	Source        string        `json:"source"`
+	From          string        `json:"from,omitempty"`
+	To            string        `json:"to,omitempty"`
+	Meals         []PlannedMeal `json:"-"`
+	MealCount     int           `json:"meals"`
+	ItemCount     int           `json:"items"`
+	MatchedItems  int           `json:"matched_items"`   // Associados a um alimento da TACO
+	QuickAddItems int           `json:"quick_add_items"` // Gravados com os valores originais
+	Duplicates    int           `json:"duplicates"`      // Refeições já importadas antes
+	Warnings      []Warning     `json:"warnings"`
+}
+
+// Options controla a montagem do plano
+type Options struct {
+	Source     string
+	Location   *time.Location
+	MatchFoods bool
+}
+
+// Build agrupa as linhas por dia e refeição, associa os alimentos à TACO
+// (quando há nome e peso em gramas) e descarta as refeições já existentes no
+// mesmo horário. Nada é gravado.
+func Build(db *gorm.DB, userID uint, entries []Entry, foods []models.Food, options Options) (Plan, error) {
+	plan := Plan{Source: options.Source, Warnings: []Warning{}}
+
+	type mealKey struct{ date, mealType, clock string }
+	groups := map[mealKey][]Entry{}
+	var keys []mealKey
+	for _, entry := range entries {
+		clock := entry.Time
+		if clock == "" {
+			clock = defaultMealTimes[entry.MealType]
+		}
+		key := mealKey{entry.Date, entry.MealType, clock}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], entry)
+	}
+	if len(keys) == 0 {
+		return plan, nil
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].date != keys[j].date {
+			return keys[i].date < keys[j].date
+		}
+		return keys[i].clock < keys[j].clock
+	})
+	plan.From, plan.To = keys[0].date, keys[len(keys)-1].date
+
+	existing, err := existingMealTimes(db, userID, plan.From, plan.To, options.Location)
+	if err != nil {
+		return plan, err
+	}
+
+	for _, key := range keys {
+		at, err := time.ParseInLocation(models.DateLayout+" 15:04", key.date+" "+key.clock, options.Location)
+		if err != nil {
+			return plan, err
+		}
+		if existing[at.Unix()] {
+			plan.Duplicates++
+			continue
+		}
+
+		meal := PlannedMeal{Date: key.date, MealType: key.mealType, At: at}
+		for _, entry := range groups[key] {
+			item, matched := buildItem(entry, foods, options)
+			if matched {
+				plan.MatchedItems++
+			} else {
+				plan.QuickAddItems++
+			}
+			meal.Items = append(meal.Items, item)
+		}
+		plan.Meals = append(plan.Meals, meal)
+		plan.ItemCount += len(meal.Items)
+	}
+	plan.MealCount = len(plan.Meals)
+	return plan, nil
+}
+
+// buildItem cria o item do catálogo quando o alimento e o peso são
+// conhecidos; senão, um registro rápido com os valores do arquivo
+func buildItem(entry Entry, foods []models.Food, options Options) (models.MealItem, bool) {
+	grams, hasGrams := entryGrams(entry)
+	if options.MatchFoods && entry.Food != "" && hasGrams {
+		matches := mealparser.Resolve(entry.Food, foods, 1)
+		if len(matches) > 0 && matches[0].Score >= minMatchScore {
+			food := matches[0].Food
+			item := models.MealItem{FoodID: &food.ID, Amount: grams, Quantity: entry.Quantity, Unit: entry.Unit}
+			item.SnapshotFrom(food)
+			return item, true
+		}
+	}
+
+	item := models.MealItem{
+		Label:     entry.Food,
+		Quantity:  entry.Quantity,
+		Unit:      entry.Unit,
+		Amount:    grams,
+		Nutrients: entry.Nutrients,
+	}
+	if item.Label == "" {
+		item.Label = fmt.Sprintf("Total da refeição (%s)", sourceLabels[options.Source])
+	}
+	// Calorias já foram validadas na leitura do CSV
+	_ = item.PrepareQuickAdd()
+	return item, false
+}
+
+// entryGrams converte a quantidade para gramas quando a unidade é de peso ou volume
+func entryGrams(entry Entry) (float64, bool) {
+	if entry.Quantity <= 0 {
+		return 0, false
+	}
+	switch strings.ToLower(strings.TrimSuffix(entry.Unit, ".")) {
+	case "g", "gr", "gram", "grams", "grama", "gramas", "ml":
+		return entry.Quantity, true
+	case "kg":
+		return entry.Quantity * 1000, true
+	case "l":
+		return entry.Quantity * 1000, true
+	}
+	return 0, false
+}
+
+// existingMealTimes retorna os horários (em segundos) das refeições do usuário no período
+func existingMealTimes(db *gorm.DB, userID uint, from, to string, loc *time.Location) (map[int64]bool, error) {
+	start, err := time.ParseInLocation(models.DateLayout, from, loc)
+	if err != nil {
+		return nil, err
+	}
+	end, err := time.ParseInLocation(models.DateLayout, to, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	var times []time.Time
+	err = db.Model(&models.Meal{}).
+		Where("user_id = ? AND created_at >= ? AND created_at < ?", userID, start, end.AddDate(0, 0, 1)).
+		Pluck("created_at", &times).Error
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[int64]bool, len(times))
+	for _, at := range times {
+		existing[at.Unix()] = true
+	}
+	return existing, nil
+}
+
+// Apply grava as refeições do plano e refaz os totais diários do período
+func Apply(db *gorm.DB, userID uint, plan Plan) error {
+	if len(plan.Meals) == 0 {
+		return nil
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		meals := make([]models.Meal, len(plan.Meals))
+		for i, planned := range plan.Meals {
+			meals[i] = models.Meal{UserID: userID, MealType: planned.MealType, CreatedAt: planned.At, Items: planned.Items}
+		}
+		if err := tx.CreateInBatches(meals, 100).Error; err != nil {
+			return err
+		}
+		_, err := models.RebuildDailySummaries(tx, []uint{userID}, plan.From, plan.To)
+		return err
+	})
+}
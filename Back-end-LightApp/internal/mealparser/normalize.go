@@ -0,0 +1,62 @@
+package mealparser
+
+import (
+	"strings"
+	"unicode"
+)
+
+var accentReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "ê", "e", "è", "e", "ë", "e",
+	"í", "i", "î", "i", "ì", "i", "ï", "i",
+	"ó", "o", "ô", "o", "õ", "o", "ò", "o", "ö", "o",
+	"ú", "u", "û", "u", "ù", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+)
+
+// Normalize deixa o texto em minúsculas e sem acentos
+func Normalize(text string) string {
+	return accentReplacer.Replace(strings.ToLower(strings.TrimSpace(text)))
+}
+
+// words separa o texto normalizado em palavras (letras e dígitos)
+func words(text string) []string {
+	return strings.FieldsFunc(Normalize(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// stem reduz plural e gênero para comparar "ovos"/"ovo" e "cozida"/"cozido"
+func stem(word string) string {
+	switch {
+	case strings.HasSuffix(word, "oes"), strings.HasSuffix(word, "aes"):
+		word = word[:len(word)-3] + "ao"
+	case strings.HasSuffix(word, "is") && len(word) > 4:
+		word = word[:len(word)-2] + "l"
+	case strings.HasSuffix(word, "s") && len(word) > 3:
+		word = word[:len(word)-1]
+	}
+	if strings.HasSuffix(word, "a") && len(word) > 4 {
+		word = word[:len(word)-1] + "o"
+	}
+	return word
+}
+
+// stopwords são ignoradas na comparação com o nome dos alimentos
+var stopwords = map[string]bool{
+	"de": true, "do": true, "da": true, "dos": true, "das": true,
+	"com": true, "sem": true, "em": true, "no": true, "na": true,
+	"o": true, "a": true, "os": true, "as": true, "um": true, "uma": true,
+}
+
+// stems retorna os radicais das palavras relevantes do texto
+func stems(text string) []string {
+	var result []string
+	for _, word := range words(text) {
+		if stopwords[word] {
+			continue
+		}
+		result = append(result, stem(word))
+	}
+	return result
+}
@@ -0,0 +1,385 @@
+// Package mealparser interpreta frases em português como "2 ovos mexidos e
+// 1 concha de feijão" e propõe itens de refeição a partir do catálogo.
+// Funciona offline, apenas com as tabelas deste pacote.
+package mealparser
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Tipos de unidade, usados para estimar a confiança da gramagem
+const (
+	UnitKindMass      = "mass"      // g, kg
+	UnitKindVolume    = "volume"    // ml, l (densidade considerada 1)
+	UnitKindHousehold = "household" // colher, concha, xícara...
+	UnitKindCount     = "count"     // unidades do próprio alimento
+	UnitKindPortion   = "portion"   // sem quantidade ou unidade informadas
+)
+
+// DefaultPortionGrams é usado quando a frase não informa quantidade nem unidade
+// e quando não se conhece o peso de uma unidade do alimento
+const DefaultPortionGrams = 100.0
+
+// ParsedItem é um trecho da frase já separado em quantidade, unidade e alimento
+type ParsedItem struct {
+	Text       string  `json:"text"`
+	Quantity   float64 `json:"quantity"`
+	Unit       string  `json:"unit"`
+	UnitKind   string  `json:"unit_kind"`
+	Grams      float64 `json:"grams"`
+	FoodPhrase string  `json:"food_phrase"`
+	// GramsEstimated indica que a gramagem veio de um peso padrão e não de uma
+	// medida conhecida para o alimento
+	GramsEstimated bool `json:"grams_estimated"`
+}
+
+type measure struct {
+	name  string // Nome canônico retornado na unidade
+	kind  string
+	grams float64 // Gramas por unidade
+}
+
+// measures mapeia as formas escritas (já normalizadas) para as medidas.
+// As formas com mais palavras são testadas primeiro.
+var measures = map[string]measure{
+	"g":      {"g", UnitKindMass, 1},
+	"gr":     {"g", UnitKindMass, 1},
+	"grama":  {"g", UnitKindMass, 1},
+	"gramas": {"g", UnitKindMass, 1},
+	"kg":     {"kg", UnitKindMass, 1000},
+	"quilo":  {"kg", UnitKindMass, 1000},
+	"quilos": {"kg", UnitKindMass, 1000},
+	"ml":     {"ml", UnitKindVolume, 1},
+	"l":      {"l", UnitKindVolume, 1000},
+	"litro":  {"l", UnitKindVolume, 1000},
+	"litros": {"l", UnitKindVolume, 1000},
+
+	"colher de sopa":        {"colher de sopa", UnitKindHousehold, 15},
+	"colheres de sopa":      {"colher de sopa", UnitKindHousehold, 15},
+	"colher de sobremesa":   {"colher de sobremesa", UnitKindHousehold, 10},
+	"colheres de sobremesa": {"colher de sobremesa", UnitKindHousehold, 10},
+	"colher de cha":         {"colher de chá", UnitKindHousehold, 5},
+	"colheres de cha":       {"colher de chá", UnitKindHousehold, 5},
+	"colher de cafe":        {"colher de café", UnitKindHousehold, 2},
+	"colheres de cafe":      {"colher de café", UnitKindHousehold, 2},
+	"colher":                {"colher de sopa", UnitKindHousehold, 15},
+	"colheres":              {"colher de sopa", UnitKindHousehold, 15},
+	"concha":                {"concha", UnitKindHousehold, 80},
+	"conchas":               {"concha", UnitKindHousehold, 80},
+	"escumadeira":           {"escumadeira", UnitKindHousehold, 50},
+	"escumadeiras":          {"escumadeira", UnitKindHousehold, 50},
+	"xicara de cha":         {"xícara", UnitKindHousehold, 240},
+	"xicaras de cha":        {"xícara", UnitKindHousehold, 240},
+	"xicara de cafe":        {"xícara de café", UnitKindHousehold, 50},
+	"xicaras de cafe":       {"xícara de café", UnitKindHousehold, 50},
+	"xicara":                {"xícara", UnitKindHousehold, 240},
+	"xicaras":               {"xícara", UnitKindHousehold, 240},
+	"xicrinha":              {"xícara de café", UnitKindHousehold, 50},
+	"cafezinho":             {"xícara de café", UnitKindHousehold, 50},
+	"copo americano":        {"copo americano", UnitKindHousehold, 190},
+	"copos americanos":      {"copo americano", UnitKindHousehold, 190},
+	"copo":                  {"copo", UnitKindHousehold, 200},
+	"copos":                 {"copo", UnitKindHousehold, 200},
+	"caneca":                {"caneca", UnitKindHousehold, 300},
+	"canecas":               {"caneca", UnitKindHousehold, 300},
+	"fatia":                 {"fatia", UnitKindHousehold, 25},
+	"fatias":                {"fatia", UnitKindHousehold, 25},
+	"pedaco":                {"pedaço", UnitKindHousehold, 50},
+	"pedacos":               {"pedaço", UnitKindHousehold, 50},
+	"prato":                 {"prato", UnitKindHousehold, 250},
+	"pratos":                {"prato", UnitKindHousehold, 250},
+	"porcao":                {"porção", UnitKindHousehold, 100},
+	"porcoes":               {"porção", UnitKindHousehold, 100},
+	"pote":                  {"pote", UnitKindHousehold, 170},
+	"potes":                 {"pote", UnitKindHousehold, 170},
+	"lata":                  {"lata", UnitKindHousehold, 350},
+	"latas":                 {"lata", UnitKindHousehold, 350},
+	"garrafa":               {"garrafa", UnitKindHousehold, 500},
+	"garrafas":              {"garrafa", UnitKindHousehold, 500},
+	"punhado":               {"punhado", UnitKindHousehold, 30},
+	"punhados":              {"punhado", UnitKindHousehold, 30},
+	"file":                  {"filé", UnitKindHousehold, 100},
+	"files":                 {"filé", UnitKindHousehold, 100},
+	"bife":                  {"bife", UnitKindHousehold, 100},
+	"bifes":                 {"bife", UnitKindHousehold, 100},
+	"unidade":               {"unidade", UnitKindCount, 0},
+	"unidades":              {"unidade", UnitKindCount, 0},
+	"un":                    {"unidade", UnitKindCount, 0},
+	"und":                   {"unidade", UnitKindCount, 0},
+	"duzia":                 {"dúzia", UnitKindCount, 0},
+	"duzias":                {"dúzia", UnitKindCount, 0},
+}
+
+// maxMeasureWords é o maior número de palavras de uma forma em measures
+const maxMeasureWords = 3
+
+// unitWeights guarda o peso médio (g) de uma unidade de alimentos contáveis,
+// indexado pelo radical da primeira palavra do alimento
+var unitWeights = map[string]float64{
+	stem("ovo"):       50,
+	stem("banana"):    70,
+	stem("maca"):      130,
+	stem("laranja"):   180,
+	stem("tangerina"): 135,
+	stem("pera"):      130,
+	stem("kiwi"):      75,
+	stem("pao"):       50,
+	stem("paozinho"):  50,
+	stem("tomate"):    100,
+	stem("batata"):    130,
+	stem("cenoura"):   70,
+	stem("biscoito"):  6,
+	stem("bolacha"):   6,
+	stem("torrada"):   10,
+	stem("tapioca"):   70,
+	stem("coxinha"):   70,
+	stem("pastel"):    60,
+	stem("esfiha"):    70,
+	stem("iogurte"):   170,
+	stem("bombom"):    20,
+	stem("salsicha"):  50,
+	stem("coxa"):      100,
+	stem("sobrecoxa"): 110,
+	stem("asa"):       40,
+}
+
+// sizeModifiers ajustam a medida caseira ("concha grande", "colher cheia")
+var sizeModifiers = map[string]float64{
+	"pequeno": 0.7, "pequena": 0.7, "pequenos": 0.7, "pequenas": 0.7,
+	"medio": 1, "media": 1, "medios": 1, "medias": 1,
+	"grande": 1.4, "grandes": 1.4,
+	"cheio": 1.3, "cheia": 1.3, "cheios": 1.3, "cheias": 1.3,
+	"raso": 0.7, "rasa": 0.7, "rasos": 0.7, "rasas": 0.7,
+}
+
+// numberWords converte quantidades escritas por extenso
+var numberWords = map[string]float64{
+	"um": 1, "uma": 1, "dois": 2, "duas": 2, "tres": 3, "quatro": 4,
+	"cinco": 5, "seis": 6, "sete": 7, "oito": 8, "nove": 9, "dez": 10,
+	"onze": 11, "doze": 12, "quinze": 15, "vinte": 20, "trinta": 30,
+	"cem": 100, "duzentos": 200, "duzentas": 200, "trezentos": 300, "trezentas": 300,
+	"meio": 0.5, "meia": 0.5, "metade": 0.5, "um terco": 1.0 / 3, "um quarto": 0.25,
+}
+
+var (
+	thousands     = regexp.MustCompile(`\b[1-9]\d{0,2}(?:\.\d{3})+(?:[^\d.]|$)`)
+	decimalComma  = regexp.MustCompile(`(\d),(\d)`)
+	attachedUnit  = regexp.MustCompile(`(\d)([a-z]+)`)
+	andHalf       = regexp.MustCompile(`\b(` + wholeQuantityPattern() + `) e (?:meia|meio)\b`)
+	itemSeparator = regexp.MustCompile(`\s*(?:,|;|\+|\be\b|\bmais\b|\n)\s*`)
+)
+
+// wholeQuantityPattern casa as quantidades inteiras que aceitam "e meia"
+// depois: números ("2 e meia") e números por extenso ("uma e meia")
+func wholeQuantityPattern() string {
+	words := []string{`\d+(?:\.\d+)?`}
+	for word, value := range numberWords {
+		if value >= 1 && !strings.Contains(word, " ") {
+			words = append(words, word)
+		}
+	}
+	sort.Strings(words[1:])
+	return strings.Join(words, "|")
+}
+
+// foodMeasures são medidas que também nomeiam o alimento ("um bife")
+var foodMeasures = map[string]bool{"bife": true, "filé": true}
+
+// connectors aparecem entre a medida e o alimento ("1 concha de feijão")
+var connectors = map[string]bool{"de": true, "do": true, "da": true, "dos": true, "das": true}
+
+// Parse separa a frase em itens. Trechos sem alimento são descartados.
+func Parse(text string) []ParsedItem {
+	normalized := Normalize(text)
+	normalized = strings.ReplaceAll(normalized, "½", " 1/2")
+	// Ponto como separador de milhar: "1.000 g"
+	normalized = thousands.ReplaceAllStringFunc(normalized, func(number string) string {
+		return strings.ReplaceAll(number, ".", "")
+	})
+	normalized = decimalComma.ReplaceAllString(normalized, "$1.$2")
+	normalized = attachedUnit.ReplaceAllString(normalized, "$1 $2")
+	// "uma e meia xícara" não deve ser separado em dois itens; "e meia" só se
+	// junta à quantidade logo antes dele
+	normalized = andHalf.ReplaceAllString(normalized, "$1 emeia")
+
+	var items []ParsedItem
+	for _, segment := range itemSeparator.Split(normalized, -1) {
+		// "um pão e meio": o "meio" solto completa o item anterior
+		if half := strings.TrimSpace(segment); (half == "meio" || half == "meia") && len(items) > 0 {
+			items[len(items)-1].addHalf(half)
+			continue
+		}
+		if item, ok := parseSegment(segment); ok {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// addHalf acrescenta meia unidade ao item, mantendo o peso por unidade
+func (item *ParsedItem) addHalf(word string) {
+	item.Grams *= (item.Quantity + 0.5) / item.Quantity
+	item.Quantity += 0.5
+	item.Text += " e " + word
+}
+
+func parseSegment(segment string) (ParsedItem, bool) {
+	tokens := strings.Fields(segment)
+	if len(tokens) == 0 {
+		return ParsedItem{}, false
+	}
+
+	item := ParsedItem{Text: strings.ReplaceAll(strings.Join(tokens, " "), "emeia", "e meia")}
+
+	quantity, used := parseQuantity(tokens)
+	explicitQuantity := used > 0
+	if !explicitQuantity {
+		quantity = 1
+	}
+	tokens = tokens[used:]
+
+	// Modificador antes da medida: "1 grande concha"
+	sizeFactor := 1.0
+	if len(tokens) > 0 {
+		if factor, ok := sizeModifiers[tokens[0]]; ok {
+			sizeFactor = factor
+			tokens = tokens[1:]
+		}
+	}
+
+	unit, measureWords := matchMeasure(tokens)
+	// Frações por extenso ligam-se à medida: "um terço de xícara de leite"
+	if measureWords == 0 && explicitQuantity && len(tokens) > 1 && connectors[tokens[0]] {
+		if m, words := matchMeasure(tokens[1:]); words > 0 {
+			unit, measureWords = m, words
+			tokens = tokens[1:]
+		}
+	}
+	measureText := strings.Join(tokens[:measureWords], " ")
+	tokens = tokens[measureWords:]
+
+	// Modificador depois da medida: "1 colher cheia de arroz"
+	if measureWords > 0 && len(tokens) > 0 {
+		if factor, ok := sizeModifiers[tokens[0]]; ok {
+			sizeFactor *= factor
+			tokens = tokens[1:]
+		}
+	}
+
+	for len(tokens) > 0 && connectors[tokens[0]] {
+		tokens = tokens[1:]
+	}
+
+	item.FoodPhrase = strings.Join(tokens, " ")
+	// Sem outro substantivo, a medida é o próprio alimento: "um bife", "2 filés"
+	if item.FoodPhrase == "" && foodMeasures[unit.name] {
+		item.FoodPhrase = measureText
+	}
+	if item.FoodPhrase == "" {
+		return ParsedItem{}, false
+	}
+	item.Quantity = quantity
+
+	switch {
+	case measureWords == 0 && !explicitQuantity:
+		item.Unit = "porção"
+		item.UnitKind = UnitKindPortion
+		item.Grams = DefaultPortionGrams
+		item.GramsEstimated = true
+	case measureWords == 0, unit.kind == UnitKindCount:
+		if unit.name == "dúzia" {
+			quantity *= 12
+		}
+		item.Unit = "unidade"
+		item.UnitKind = UnitKindCount
+		item.Quantity = quantity
+		weight, known := unitWeight(item.FoodPhrase)
+		item.Grams = quantity * weight * sizeFactor
+		item.GramsEstimated = !known
+	default:
+		item.Unit = unit.name
+		item.UnitKind = unit.kind
+		item.Grams = quantity * unit.grams * sizeFactor
+	}
+
+	return item, true
+}
+
+// parseQuantity lê a quantidade no início dos tokens e retorna quantos foram usados
+func parseQuantity(tokens []string) (float64, int) {
+	if len(tokens) == 0 {
+		return 0, 0
+	}
+
+	// "meia dúzia", "um terço"
+	if len(tokens) > 1 {
+		if value, ok := numberWords[tokens[0]+" "+tokens[1]]; ok {
+			return withHalf(value, tokens, 2)
+		}
+	}
+
+	if value, ok := parseNumber(tokens[0]); ok {
+		// "1 1/2 xícara"
+		if len(tokens) > 1 && strings.Contains(tokens[1], "/") {
+			if fraction, ok := parseNumber(tokens[1]); ok && fraction < 1 {
+				return withHalf(value+fraction, tokens, 2)
+			}
+		}
+		return withHalf(value, tokens, 1)
+	}
+
+	if value, ok := numberWords[tokens[0]]; ok {
+		// "um" e "uma" antes de uma medida ou alimento são quantidade; "meio" sozinho também
+		return withHalf(value, tokens, 1)
+	}
+
+	return 0, 0
+}
+
+// withHalf soma meia unidade quando a quantidade vem seguida de "e meia"
+func withHalf(value float64, tokens []string, used int) (float64, int) {
+	if len(tokens) > used && tokens[used] == "emeia" {
+		return value + 0.5, used + 1
+	}
+	return value, used
+}
+
+func parseNumber(token string) (float64, bool) {
+	if numerator, denominator, ok := strings.Cut(token, "/"); ok {
+		n, errN := strconv.ParseFloat(numerator, 64)
+		d, errD := strconv.ParseFloat(denominator, 64)
+		if errN != nil || errD != nil || d == 0 {
+			return 0, false
+		}
+		return n / d, true
+	}
+	value, err := strconv.ParseFloat(token, 64)
+	if err != nil || value <= 0 {
+		return 0, false
+	}
+	return value, true
+}
+
+// matchMeasure procura a medida mais longa no início dos tokens
+func matchMeasure(tokens []string) (measure, int) {
+	for size := min(maxMeasureWords, len(tokens)); size > 0; size-- {
+		if m, ok := measures[strings.Join(tokens[:size], " ")]; ok {
+			return m, size
+		}
+	}
+	return measure{}, 0
+}
+
+// unitWeight estima o peso de uma unidade do alimento pela primeira palavra
+func unitWeight(foodPhrase string) (float64, bool) {
+	foodStems := stems(foodPhrase)
+	if len(foodStems) > 0 {
+		if weight, ok := unitWeights[foodStems[0]]; ok {
+			return weight, true
+		}
+	}
+	return DefaultPortionGrams, false
+}
@@ -0,0 +1,152 @@
+package mealparser
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	type want struct {
+		quantity float64
+		unit     string
+		kind     string
+		food     string
+		grams    float64
+	}
+	tests := []struct {
+		text string
+		want []want
+	}{
+		// Números por extenso
+		{"meia xícara de arroz", []want{{0.5, "xícara", UnitKindHousehold, "arroz", 120}}},
+		{"meio copo de suco", []want{{0.5, "copo", UnitKindHousehold, "suco", 100}}},
+		{"duas bananas", []want{{2, "unidade", UnitKindCount, "bananas", 140}}},
+		{"duas conchas de feijão", []want{{2, "concha", UnitKindHousehold, "feijao", 160}}},
+		{"três ovos", []want{{3, "unidade", UnitKindCount, "ovos", 150}}},
+		{"uma e meia xícara de arroz", []want{{1.5, "xícara", UnitKindHousehold, "arroz", 360}}},
+		{"duas e meia colheres de sopa de aveia", []want{{2.5, "colher de sopa", UnitKindHousehold, "aveia", 37.5}}},
+		{"2 e meio copos de suco", []want{{2.5, "copo", UnitKindHousehold, "suco", 500}}},
+		{"um pão e meio", []want{{1.5, "unidade", UnitKindCount, "pao", 75}}},
+		{"meia dúzia de ovos", []want{{6, "unidade", UnitKindCount, "ovos", 300}}},
+		{"uma dúzia de ovos", []want{{12, "unidade", UnitKindCount, "ovos", 600}}},
+		{"um terço de xícara de leite", []want{{1.0 / 3, "xícara", UnitKindHousehold, "leite", 80}}},
+		{"um quarto de xícara de aveia", []want{{0.25, "xícara", UnitKindHousehold, "aveia", 60}}},
+
+		// Vírgula decimal e frações
+		{"1,5 kg de batata", []want{{1.5, "kg", UnitKindMass, "batata", 1500}}},
+		{"0,5 l de suco", []want{{0.5, "l", UnitKindVolume, "suco", 500}}},
+		{"2,5 colheres de sopa de azeite", []want{{2.5, "colher de sopa", UnitKindHousehold, "azeite", 37.5}}},
+		{"1/2 xícara de feijão", []want{{0.5, "xícara", UnitKindHousehold, "feijao", 120}}},
+		{"1 1/2 colher de sopa de azeite", []want{{1.5, "colher de sopa", UnitKindHousehold, "azeite", 22.5}}},
+		{"½ copo de leite", []want{{0.5, "copo", UnitKindHousehold, "leite", 100}}},
+		{"3/4 de xícara de arroz", []want{{0.75, "xícara", UnitKindHousehold, "arroz", 180}}},
+
+		// Ponto como separador de milhar
+		{"1.000 g de arroz", []want{{1000, "g", UnitKindMass, "arroz", 1000}}},
+		{"1.000g de arroz", []want{{1000, "g", UnitKindMass, "arroz", 1000}}},
+		{"1.250,5 g de arroz", []want{{1250.5, "g", UnitKindMass, "arroz", 1250.5}}},
+		{"1.5 kg de batata", []want{{1.5, "kg", UnitKindMass, "batata", 1500}}},
+
+		// Massa e volume, com e sem espaço
+		{"200g de frango", []want{{200, "g", UnitKindMass, "frango", 200}}},
+		{"150 g de arroz", []want{{150, "g", UnitKindMass, "arroz", 150}}},
+		{"150 gramas de arroz", []want{{150, "g", UnitKindMass, "arroz", 150}}},
+		{"300ml de leite", []want{{300, "ml", UnitKindVolume, "leite", 300}}},
+		{"1 litro de água", []want{{1, "l", UnitKindVolume, "agua", 1000}}},
+
+		// Medidas caseiras e modificadores de tamanho
+		{"1 colher de sopa de azeite", []want{{1, "colher de sopa", UnitKindHousehold, "azeite", 15}}},
+		{"2 colheres de chá de açúcar", []want{{2, "colher de chá", UnitKindHousehold, "acucar", 10}}},
+		{"1 colher de sobremesa de mel", []want{{1, "colher de sobremesa", UnitKindHousehold, "mel", 10}}},
+		{"1 concha grande de feijão", []want{{1, "concha", UnitKindHousehold, "feijao", 112}}},
+		{"1 colher cheia de arroz", []want{{1, "colher de sopa", UnitKindHousehold, "arroz", 19.5}}},
+		{"1 colher rasa de açúcar", []want{{1, "colher de sopa", UnitKindHousehold, "acucar", 10.5}}},
+		{"3 fatias de pão de forma", []want{{3, "fatia", UnitKindHousehold, "pao de forma", 75}}},
+		{"1 copo americano de suco", []want{{1, "copo americano", UnitKindHousehold, "suco", 190}}},
+		{"1 xícara de chá de arroz", []want{{1, "xícara", UnitKindHousehold, "arroz", 240}}},
+		{"2 escumadeiras de arroz", []want{{2, "escumadeira", UnitKindHousehold, "arroz", 100}}},
+		{"1 pote de iogurte", []want{{1, "pote", UnitKindHousehold, "iogurte", 170}}},
+		{"1 lata de refrigerante", []want{{1, "lata", UnitKindHousehold, "refrigerante", 350}}},
+
+		// A medida que também nomeia o alimento
+		{"um bife", []want{{1, "bife", UnitKindHousehold, "bife", 100}}},
+		{"2 bifes grandes", []want{{2, "bife", UnitKindHousehold, "bifes", 280}}},
+		{"um filé", []want{{1, "filé", UnitKindHousehold, "file", 100}}},
+		{"um filé de frango", []want{{1, "filé", UnitKindHousehold, "frango", 100}}},
+
+		// Unidades e porção padrão
+		{"2 ovos", []want{{2, "unidade", UnitKindCount, "ovos", 100}}},
+		{"3 pães", []want{{3, "unidade", UnitKindCount, "paes", 150}}},
+		{"arroz", []want{{1, "porção", UnitKindPortion, "arroz", DefaultPortionGrams}}},
+
+		// Separação por "e", vírgula e outros conectores
+		{"2 ovos mexidos e 1 concha de feijão", []want{
+			{2, "unidade", UnitKindCount, "ovos mexidos", 100},
+			{1, "concha", UnitKindHousehold, "feijao", 80},
+		}},
+		{"arroz, feijão e salada", []want{
+			{1, "porção", UnitKindPortion, "arroz", 100},
+			{1, "porção", UnitKindPortion, "feijao", 100},
+			{1, "porção", UnitKindPortion, "salada", 100},
+		}},
+		{"arroz e feijão, 2 ovos", []want{
+			{1, "porção", UnitKindPortion, "arroz", 100},
+			{1, "porção", UnitKindPortion, "feijao", 100},
+			{2, "unidade", UnitKindCount, "ovos", 100},
+		}},
+		{"arroz e meia xícara de feijão", []want{
+			{1, "porção", UnitKindPortion, "arroz", 100},
+			{0.5, "xícara", UnitKindHousehold, "feijao", 120},
+		}},
+		{"2 ovos e meio mamão", []want{
+			{2, "unidade", UnitKindCount, "ovos", 100},
+			{0.5, "unidade", UnitKindCount, "mamao", 50},
+		}},
+		{"pão; manteiga + café mais suco", []want{
+			{1, "porção", UnitKindPortion, "pao", 100},
+			{1, "porção", UnitKindPortion, "manteiga", 100},
+			{1, "porção", UnitKindPortion, "cafe", 100},
+			{1, "porção", UnitKindPortion, "suco", 100},
+		}},
+
+		// Trechos sem alimento são descartados
+		{"um copo", nil},
+		{"  ", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			items := Parse(tt.text)
+			if len(items) != len(tt.want) {
+				t.Fatalf("Parse(%q) = %d itens %+v, esperado %d", tt.text, len(items), items, len(tt.want))
+			}
+			for i, w := range tt.want {
+				got := items[i]
+				if math.Abs(got.Quantity-w.quantity) > 1e-9 || got.Unit != w.unit || got.UnitKind != w.kind ||
+					got.FoodPhrase != w.food || math.Abs(got.Grams-w.grams) > 1e-9 {
+					t.Errorf("item %d = {%v %q %q %q %v}, esperado %+v",
+						i, got.Quantity, got.Unit, got.UnitKind, got.FoodPhrase, got.Grams, w)
+				}
+			}
+		})
+	}
+}
+
+func TestParseGramsEstimated(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"2 ovos", false},
+		{"2 jacas", true},
+		{"arroz", true},
+		{"1 concha de feijão", false},
+		{"200g de frango", false},
+	}
+	for _, tt := range tests {
+		items := Parse(tt.text)
+		if len(items) != 1 || items[0].GramsEstimated != tt.want {
+			t.Errorf("Parse(%q) = %+v, esperado GramsEstimated=%v", tt.text, items, tt.want)
+		}
+	}
+}
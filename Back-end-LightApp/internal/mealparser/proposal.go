@@ -0,0 +1,67 @@
+package mealparser
+
+import (
+	"math"
+
+	"github.com/juliapinheiro42/LightApp/internal/models"
+)
+
+// MaxAlternatives é o número de outros candidatos sugeridos por item
+const MaxAlternatives = 3
+
+// Proposal é um item de refeição sugerido para o usuário confirmar
+type Proposal struct {
+	ParsedItem
+	FoodID       *uint            `json:"food_id"`
+	Food         *models.Food     `json:"food"`
+	Nutrients    models.Nutrients `json:"nutrients"`
+	Confidence   float64          `json:"confidence"`             // Entre 0 e 1
+	Substitution string           `json:"substitution,omitempty"` // Aviso quando o alimento substitui o citado
+	Alternatives []Match          `json:"alternatives"`
+}
+
+// substitutionConfidence reduz a confiança quando o alimento proposto
+// substitui o citado
+const substitutionConfidence = 0.6
+
+// unitConfidence reflete quão confiável é a gramagem para cada tipo de unidade
+var unitConfidence = map[string]float64{
+	UnitKindMass:      1,
+	UnitKindVolume:    1,
+	UnitKindHousehold: 0.9,
+	UnitKindCount:     0.9,
+	UnitKindPortion:   0.6,
+}
+
+// Propose interpreta a frase e resolve cada trecho contra o catálogo
+func Propose(text string, foods []models.Food) []Proposal {
+	items := Parse(text)
+	proposals := make([]Proposal, 0, len(items))
+
+	for _, item := range items {
+		proposal := Proposal{ParsedItem: item, Alternatives: []Match{}}
+
+		matches := Resolve(item.FoodPhrase, foods, MaxAlternatives+1)
+		if len(matches) > 0 {
+			best := matches[0].Food
+			proposal.FoodID = &best.ID
+			proposal.Food = &best
+			proposal.Nutrients = best.NutrientsFor(item.Grams)
+			proposal.Alternatives = matches[1:]
+
+			confidence := matches[0].Score * unitConfidence[item.UnitKind]
+			if item.GramsEstimated && item.UnitKind == UnitKindCount {
+				confidence *= 0.7
+			}
+			if matches[0].Substitution != "" {
+				proposal.Substitution = matches[0].Substitution
+				confidence *= substitutionConfidence
+			}
+			proposal.Confidence = math.Round(confidence*100) / 100
+		}
+
+		proposals = append(proposals, proposal)
+	}
+
+	return proposals
+}
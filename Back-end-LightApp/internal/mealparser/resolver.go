@@ -0,0 +1,236 @@
+package mealparser
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/juliapinheiro42/LightApp/internal/models"
+)
+
+// Match é um alimento do catálogo candidato a um trecho da frase
+type Match struct {
+	Food  models.Food `json:"food"`
+	Score float64     `json:"score"` // Entre 0 e 1
+	// Substitution avisa que o alimento não é o citado, e sim um parecido
+	// usado por falta dele no catálogo
+	Substitution string `json:"substitution,omitempty"`
+}
+
+// Fator aplicado à pontuação dos alimentos sem valores analisados
+const noDataPenalty = 0.5
+
+// defaultFoods indica o alimento usado quando a frase cita só o nome genérico.
+// O valor substitui o trecho na busca.
+var defaultFoods = indexByStems(map[string]string{
+	"arroz":        "arroz tipo 1 cozido",
+	"feijao":       "feijao carioca cozido",
+	"ovo":          "ovo galinha inteiro cozido",
+	"ovo mexido":   "ovo galinha inteiro frito",
+	"ovo frito":    "ovo galinha inteiro frito",
+	"ovo cozido":   "ovo galinha inteiro cozido",
+	"pao":          "pao trigo frances",
+	"pao frances":  "pao trigo frances",
+	"paozinho":     "pao trigo frances",
+	"pao de forma": "pao trigo forma integral",
+	"cafe":         "cafe infusao",
+	// A TACO não analisou o leite de vaca fluido; o de cabra tem composição
+	// próxima e é proposto com o aviso de substitutions
+	"leite":                  "leite cabra",
+	"leite integral":         "leite cabra",
+	"leite de vaca":          "leite cabra",
+	"leite de vaca integral": "leite cabra",
+	"frango":                 "frango peito sem pele grelhado",
+	"frango grelhado":        "frango peito sem pele grelhado",
+	"frango cozido":          "frango peito sem pele cozido",
+	"frango assado":          "frango peito com pele assado",
+	"peito de frango":        "frango peito sem pele grelhado",
+	"file de frango":         "frango peito sem pele grelhado",
+	"file":                   "frango peito sem pele grelhado",
+	"banana":                 "banana prata crua",
+	"batata":                 "batata inglesa cozida",
+	"carne":                  "carne bovina patinho sem gordura grelhado",
+	"bife":                   "carne bovina patinho sem gordura grelhado",
+	"queijo":                 "queijo minas frescal",
+	"maca":                   "maca fuji com casca crua",
+	"iogurte":                "iogurte natural",
+	"acucar":                 "acucar refinado",
+	"manteiga":               "manteiga com sal",
+	"tomate":                 "tomate com semente cru",
+	"alface":                 "alface crespa crua",
+	"refrigerante":           "refrigerante tipo cola",
+	"azeite":                 "azeite oliva extra virgem",
+	"laranja":                "laranja pera crua",
+	"suco de laranja":        "laranja pera suco",
+})
+
+// substitutions são os alimentos padrão que substituem um alimento ausente do
+// catálogo, com o aviso mostrado ao usuário. A chave é o alimento padrão.
+var substitutions = map[string]string{
+	"leite cabra": "A TACO não tem leite de vaca fluido analisado; foi usado o leite de cabra, de composição próxima. Confira ou escolha outro alimento.",
+}
+
+// synonyms aproximam termos do dia a dia dos termos usados na TACO
+var synonyms = map[string]string{
+	stem("mexido"):    stem("frito"),
+	stem("cozinhado"): stem("cozido"),
+	stem("paozinho"):  stem("pao"),
+	stem("coca"):      stem("cola"),
+	stem("mussarela"): stem("mozarela"),
+	stem("mucarela"):  stem("mozarela"),
+	stem("muzarela"):  stem("mozarela"),
+}
+
+func indexByStems(phrases map[string]string) map[string]string {
+	index := make(map[string]string, len(phrases))
+	for phrase, expanded := range phrases {
+		index[strings.Join(stems(phrase), " ")] = expanded
+	}
+	return index
+}
+
+// defaultFood retorna a busca padrão para um trecho genérico
+func defaultFood(phrase string) (string, bool) {
+	expanded, ok := defaultFoods[strings.Join(stems(phrase), " ")]
+	return expanded, ok
+}
+
+// Resolve ordena os alimentos do catálogo pela semelhança com o trecho e
+// retorna os melhores candidatos (até limit)
+func Resolve(phrase string, foods []models.Food, limit int) []Match {
+	phraseStems := phraseStems(phrase)
+	if len(phraseStems) == 0 {
+		return nil
+	}
+	expanded, usedDefault := defaultFood(phrase)
+	substitution := substitutions[expanded]
+	substituteStems := stems(expanded)
+
+	specifiesState := false
+	for _, s := range phraseStems {
+		if s == stem("cru") || s == stem("crua") {
+			specifiesState = true
+		}
+	}
+
+	type scored struct {
+		match   Match
+		nameLen int
+	}
+	var candidates []scored
+	for _, food := range foods {
+		score := similarity(phraseStems, stems(food.Name))
+		if score == 0 {
+			continue
+		}
+		// Sem estado informado, prefere o alimento pronto para consumo
+		if !specifiesState && containsStem(stems(food.Name), stem("cozido")) {
+			score += 0.01
+		}
+		if usedDefault {
+			score *= 0.9
+		}
+		// Alimentos sem análise na TACO dariam um item zerado: ficam por último
+		if !food.HasNutrients() {
+			score *= noDataPenalty
+		}
+		match := Match{Food: food, Score: math.Min(score, 1)}
+		if substitution != "" && containsAllStems(stems(food.Name), substituteStems) {
+			match.Substitution = substitution
+		}
+		candidates = append(candidates, scored{match: match, nameLen: len(food.Name)})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.match.Score != b.match.Score {
+			return a.match.Score > b.match.Score
+		}
+		if a.nameLen != b.nameLen {
+			return a.nameLen < b.nameLen
+		}
+		return a.match.Food.ID < b.match.Food.ID
+	})
+
+	if limit > 0 && len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	matches := make([]Match, len(candidates))
+	for i, candidate := range candidates {
+		matches[i] = candidate.match
+		matches[i].Score = math.Round(candidate.match.Score*100) / 100
+	}
+	return matches
+}
+
+// phraseStems aplica o alimento padrão e os sinônimos aos radicais do trecho
+func phraseStems(phrase string) []string {
+	if expanded, ok := defaultFood(phrase); ok {
+		phrase = expanded
+	}
+	result := stems(phrase)
+	for i, s := range result {
+		if synonym, ok := synonyms[s]; ok {
+			result[i] = synonym
+		}
+	}
+	return result
+}
+
+// similarity combina cobertura do trecho, acerto da primeira palavra e
+// precisão em relação ao nome do alimento
+func similarity(phraseStems, nameStems []string) float64 {
+	if len(nameStems) == 0 {
+		return 0
+	}
+
+	var matched float64
+	for _, s := range phraseStems {
+		matched += bestTokenMatch(s, nameStems)
+	}
+	if matched == 0 {
+		return 0
+	}
+
+	coverage := matched / float64(len(phraseStems))
+	precision := math.Min(matched/float64(len(nameStems)), 1)
+	first := bestTokenMatch(phraseStems[0], nameStems[:1])
+
+	return 0.6*coverage + 0.25*first + 0.15*precision
+}
+
+// bestTokenMatch pontua 1 para radical igual e 0.8 para prefixo de 4+ letras
+func bestTokenMatch(s string, nameStems []string) float64 {
+	best := 0.0
+	for _, name := range nameStems {
+		switch {
+		case s == name:
+			return 1
+		case len(s) >= 4 && len(name) >= 4 && (hasPrefix(name, s) || hasPrefix(s, name)):
+			best = 0.8
+		}
+	}
+	return best
+}
+
+func hasPrefix(s, prefix string) bool {
+	return len(s) >= len(prefix) && s[:len(prefix)] == prefix
+}
+
+func containsAllStems(list, wanted []string) bool {
+	for _, s := range wanted {
+		if !containsStem(list, s) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsStem(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
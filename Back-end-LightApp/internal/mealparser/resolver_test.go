@@ -0,0 +1,180 @@
+package mealparser
+
+import (
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/juliapinheiro42/LightApp/internal/models"
+	"github.com/juliapinheiro42/LightApp/internal/taco"
+)
+
+var (
+	tacoOnce  sync.Once
+	tacoFoods []models.Food
+	tacoErr   error
+)
+
+// loadTaco lê a tabela TACO da raiz do repositório
+func loadTaco(t *testing.T) []models.Food {
+	t.Helper()
+	tacoOnce.Do(func() {
+		file, err := os.Open("../../Taco-4a-Edicao.csv")
+		if err != nil {
+			tacoErr = err
+			return
+		}
+		defer file.Close()
+		tacoFoods, tacoErr = taco.Parse(file)
+	})
+	if tacoErr != nil {
+		t.Fatalf("Erro ao ler a TACO: %v", tacoErr)
+	}
+	return tacoFoods
+}
+
+func TestResolveTaco(t *testing.T) {
+	foods := loadTaco(t)
+
+	tests := []struct {
+		phrase string
+		want   string
+	}{
+		// Nomes genéricos usam o alimento padrão
+		{"arroz", "Arroz, tipo 1, cozido"},
+		{"feijão", "Feijão, carioca, cozido"},
+		{"ovo", "Ovo, de galinha, inteiro, cozido/10minutos"},
+		{"ovos", "Ovo, de galinha, inteiro, cozido/10minutos"},
+		{"ovos mexidos", "Ovo, de galinha, inteiro, frito"},
+		{"pão", "Pão, trigo, francês"},
+		{"pãozinho", "Pão, trigo, francês"},
+		{"pão de forma", "Pão, trigo, forma, integral"},
+		{"café", "Café, infusão 10%"},
+		{"leite", "Leite, de cabra"},
+		{"leite de vaca integral", "Leite, de cabra"},
+		{"frango", "Frango, peito, sem pele, grelhado"},
+		{"frango grelhado", "Frango, peito, sem pele, grelhado"},
+		{"frango cozido", "Frango, peito, sem pele, cozido"},
+		{"peito de frango", "Frango, peito, sem pele, grelhado"},
+		{"filé", "Frango, peito, sem pele, grelhado"},
+		{"bife", "Carne, bovina, patinho, sem gordura, grelhado"},
+		{"bifes", "Carne, bovina, patinho, sem gordura, grelhado"},
+		{"banana", "Banana, prata, crua"},
+		{"bananas", "Banana, prata, crua"},
+		{"maçã", "Maçã, Fuji, com casca, crua"},
+		{"batata", "Batata, inglesa, cozida"},
+		{"queijo", "Queijo, minas, frescal"},
+		{"açúcar", "Açúcar, refinado"},
+		{"manteiga", "Manteiga, com sal"},
+		{"azeite", "Azeite, de oliva, extra virgem"},
+		{"laranja", "Laranja, pêra, crua"},
+		{"iogurte", "Iogurte, natural"},
+		{"refrigerante", "Refrigerante, tipo cola"},
+
+		// Nomes específicos e sinônimos
+		{"arroz integral cozido", "Arroz, integral, cozido"},
+		{"feijão preto", "Feijão, preto, cozido"},
+		{"batata doce", "Batata, doce, cozida"},
+		{"cenoura crua", "Cenoura, crua"},
+		{"brócolis", "Brócolis, cozido"},
+		{"mamão papaia", "Mamão, Papaia, cru"},
+		{"salmão grelhado", "Salmão, sem pele, fresco, grelhado"},
+		{"queijo muçarela", "Queijo, mozarela"},
+		{"mussarela", "Queijo, mozarela"},
+		{"coca", "Refrigerante, tipo cola"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.phrase, func(t *testing.T) {
+			matches := Resolve(tt.phrase, foods, 3)
+			if len(matches) == 0 {
+				t.Fatalf("Resolve(%q) sem candidatos, esperado %q", tt.phrase, tt.want)
+			}
+			if got := matches[0].Food.Name; got != tt.want {
+				t.Errorf("Resolve(%q) = %q (%.2f), esperado %q", tt.phrase, got, matches[0].Score, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveWithoutMatch(t *testing.T) {
+	foods := loadTaco(t)
+	for _, phrase := range []string{"xyzw", "", "de da do"} {
+		if matches := Resolve(phrase, foods, 3); len(matches) != 0 {
+			t.Errorf("Resolve(%q) = %v, esperado nenhum candidato", phrase, matches)
+		}
+	}
+}
+
+// Alimentos sem análise na TACO não podem virar a proposta principal
+func TestResolvePenalizesFoodsWithoutData(t *testing.T) {
+	foods := loadTaco(t)
+	for _, phrase := range []string{"leite", "leite de vaca", "iogurte"} {
+		matches := Resolve(phrase, foods, 1)
+		if len(matches) == 0 || !matches[0].Food.HasNutrients() {
+			t.Errorf("Resolve(%q) = %v, esperado alimento com nutrientes", phrase, matches)
+		}
+	}
+}
+
+// O leite de cabra no lugar do de vaca é avisado e tem confiança menor
+func TestProposeFlagsSubstitution(t *testing.T) {
+	foods := loadTaco(t)
+
+	milk := Propose("1 copo de leite", foods)
+	goat := Propose("1 copo de leite de cabra", foods)
+	if len(milk) != 1 || len(goat) != 1 {
+		t.Fatalf("Propose = %+v, %+v", milk, goat)
+	}
+	if milk[0].Substitution == "" {
+		t.Errorf("leite de vaca proposto como %q sem aviso de substituição", milk[0].Food.Name)
+	}
+	if goat[0].Substitution != "" {
+		t.Errorf("leite de cabra pedido não é substituição: %q", goat[0].Substitution)
+	}
+	if milk[0].Confidence >= goat[0].Confidence {
+		t.Errorf("confiança da substituição %v, esperada menor que %v", milk[0].Confidence, goat[0].Confidence)
+	}
+	for _, alternative := range milk[0].Alternatives {
+		if alternative.Substitution != "" && alternative.Food.Name != milk[0].Food.Name {
+			t.Errorf("aviso em alternativa que não é o substituto: %q", alternative.Food.Name)
+		}
+	}
+}
+
+func TestPropose(t *testing.T) {
+	foods := loadTaco(t)
+
+	tests := []struct {
+		text     string
+		foods    []string
+		calories []float64 // Energia esperada de cada item, em kcal
+	}{
+		{"2 ovos mexidos e 1 concha de feijão", []string{"Ovo, de galinha, inteiro, frito", "Feijão, carioca, cozido"}, []float64{240, 61}},
+		{"1 copo de leite", []string{"Leite, de cabra"}, []float64{132}},
+		{"200g de frango grelhado", []string{"Frango, peito, sem pele, grelhado"}, []float64{318}},
+		{"um bife", []string{"Carne, bovina, patinho, sem gordura, grelhado"}, []float64{219}},
+		{"meia dúzia de ovos", []string{"Ovo, de galinha, inteiro, cozido/10minutos"}, []float64{438}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			proposals := Propose(tt.text, foods)
+			if len(proposals) != len(tt.foods) {
+				t.Fatalf("Propose(%q) = %d itens, esperado %d", tt.text, len(proposals), len(tt.foods))
+			}
+			for i, proposal := range proposals {
+				if proposal.Food == nil || proposal.Food.Name != tt.foods[i] {
+					t.Errorf("item %d = %+v, esperado %q", i, proposal.Food, tt.foods[i])
+					continue
+				}
+				if got := proposal.Nutrients.Calories; got < tt.calories[i]-1 || got > tt.calories[i]+1 {
+					t.Errorf("item %d: %.1f kcal, esperado %.0f", i, got, tt.calories[i])
+				}
+				if proposal.Confidence <= 0 || proposal.Confidence > 1 {
+					t.Errorf("item %d: confiança %v fora de (0, 1]", i, proposal.Confidence)
+				}
+			}
+		})
+	}
+}
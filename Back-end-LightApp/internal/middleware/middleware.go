@@ -7,6 +7,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/juliapinheiro42/LightApp/database"
+	"github.com/juliapinheiro42/LightApp/internal/models"
 	"github.com/juliapinheiro42/LightApp/internal/utils"
 )
 
@@ -55,6 +57,12 @@ func AuthMiddleware() gin.HandlerFunc {
 
 			if userID, ok := claims["user_id"].(float64); ok {
 				fmt.Println("user_id extraído do token:", userID) // Log para depuração
+				// Tokens de contas removidas ou com exclusão agendada deixam de valer
+				if !models.AccountActive(database.DB, uint(userID)) {
+					c.JSON(http.StatusUnauthorized, gin.H{"error": "Conta inexistente ou com exclusão agendada"})
+					c.Abort()
+					return
+				}
 				c.Set("user_id", uint(userID))
 			} else {
 				fmt.Println("user_id não encontrado nas claims") // Log para depuração
@@ -67,3 +75,25 @@ func AuthMiddleware() gin.HandlerFunc {
 		}
 	}
 }
+
+// AdminMiddleware permite o acesso apenas a usuários administradores.
+// Deve ser usado depois de AuthMiddleware.
+func AdminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+			c.Abort()
+			return
+		}
+
+		var user models.User
+		if err := database.DB.First(&user, userID).Error; err != nil || !user.IsAdmin {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Acesso restrito a administradores"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
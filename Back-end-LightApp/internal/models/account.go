@@ -0,0 +1,87 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AccountDeletionGrace é o prazo entre o pedido de exclusão da conta e a
+// remoção definitiva dos dados. Entrar de novo nesse prazo cancela o pedido.
+const AccountDeletionGrace = 30 * 24 * time.Hour
+
+// AccountActive indica se o usuário existe e não pediu a exclusão da conta
+func AccountActive(db *gorm.DB, userID uint) bool {
+	var count int64
+	db.Model(&User{}).Where("id = ? AND deletion_scheduled_at IS NULL", userID).Count(&count)
+	return count > 0
+}
+
+// AccountsDueForPurge retorna os usuários cujo prazo de exclusão terminou
+func AccountsDueForPurge(db *gorm.DB, now time.Time) ([]uint, error) {
+	userIDs := []uint{}
+	err := db.Unscoped().Model(&User{}).
+		Where("deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= ?", now).
+		Pluck("id", &userIDs).Error
+	return userIDs, err
+}
+
+// PurgeUserData apaga definitivamente o usuário e todos os registros ligados a
+// ele. Retorna as chaves das fotos no armazenamento, que o chamador remove
+// depois de confirmar a transação. Os registros de auditoria são mantidos:
+// guardam só o ID de quem executou a operação, que deixa de identificar alguém.
+func PurgeUserData(db *gorm.DB, userID uint) ([]string, error) {
+	var photos []MealPhoto
+	if err := db.Where("user_id = ?", userID).Find(&photos).Error; err != nil {
+		return nil, err
+	}
+	keys := []string{}
+	for _, photo := range photos {
+		keys = append(keys, photo.Key, photo.ThumbnailKey)
+	}
+
+	meals := db.Model(&Meal{}).Select("id").Where("user_id = ?", userID)
+	plans := db.Model(&MealPlan{}).Select("id").Where("user_id = ?", userID)
+	plannedMeals := db.Model(&PlannedMeal{}).Select("id").Where("plan_id IN (?)", plans)
+	lists := db.Model(&ShoppingList{}).Select("id").Where("user_id = ?", userID)
+	recipes := db.Model(&Recipe{}).Select("id").Where("user_id = ?", userID)
+
+	// Filhos antes dos pais; Unscoped para apagar de fato as tabelas com gorm.Model
+	steps := []struct {
+		model interface{}
+		query string
+		arg   interface{}
+	}{
+		{&MealItem{}, "meal_id IN (?)", meals},
+		{&MealPhoto{}, "user_id = ?", userID},
+		{&Meal{}, "user_id = ?", userID},
+		{&PlannedMealItem{}, "planned_meal_id IN (?)", plannedMeals},
+		{&PlannedMeal{}, "plan_id IN (?)", plans},
+		{&MealPlan{}, "user_id = ?", userID},
+		{&NutritionistAccess{}, "client_id = ?", userID},
+		{&NutritionistAccess{}, "nutritionist_id = ?", userID},
+		{&RecipeItem{}, "recipe_id IN (?)", recipes},
+		{&Recipe{}, "user_id = ?", userID},
+		{&ShoppingListItem{}, "list_id IN (?)", lists},
+		{&ShoppingList{}, "user_id = ?", userID},
+		{&FastingSession{}, "user_id = ?", userID},
+		{&HydrationEntry{}, "user_id = ?", userID},
+		{&WeightEntry{}, "user_id = ?", userID},
+		{&BodyMeasurement{}, "user_id = ?", userID},
+		{&DailySummary{}, "user_id = ?", userID},
+		{&RevokedToken{}, "user_id = ?", userID},
+		{&User{}, "id = ?", userID},
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, step := range steps {
+			if err := tx.Unscoped().Where(step.query, step.arg).Delete(step.model).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
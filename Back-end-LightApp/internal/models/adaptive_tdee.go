@@ -0,0 +1,184 @@
+package models
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Energia de 1 kg de variação de peso corporal
+const KcalPerKgBodyWeight = 7700.0
+
+// Janela padrão e limites da estimativa adaptativa, em dias
+const (
+	AdaptiveTDEEWindowDays = 28
+	AdaptiveTDEEMinDays    = 14
+	AdaptiveTDEEMaxDays    = 90
+)
+
+// Mínimos de dados para haver estimativa
+const (
+	adaptiveMinCompleteDays  = 7
+	adaptiveMinTrendSpan     = 7 // Dias entre o início e o fim da tendência
+	adaptiveWeighingsPerWeek = 3.0
+	// Dias com menos que esta fração da mediana provavelmente têm refeições esquecidas
+	adaptiveIncompleteDayRatio = 0.5
+)
+
+// Níveis de confiança da estimativa
+const (
+	ConfidenceLow    = "low"
+	ConfidenceMedium = "medium"
+	ConfidenceHigh   = "high"
+)
+
+// ErrInsufficientAdaptiveData indica que faltam registros ou pesagens no período
+var ErrInsufficientAdaptiveData = errors.New("Registros insuficientes: são necessários ao menos 7 dias completos de refeições e pesagens em pelo menos 7 dias de intervalo")
+
+// AdaptiveTDEE é o gasto estimado pelo balanço energético: ingestão média
+// menos a energia equivalente à variação da tendência de peso
+type AdaptiveTDEE struct {
+	From            string  `json:"from"`
+	To              string  `json:"to"`
+	Days            int     `json:"days"`
+	LoggedDays      int     `json:"logged_days"`
+	CompleteDays    int     `json:"complete_days"` // Dias registrados sem indício de refeições esquecidas
+	WeighInDays     int     `json:"weigh_in_days"`
+	AverageIntake   float64 `json:"average_intake"`
+	TrendStart      float64 `json:"trend_start"`
+	TrendEnd        float64 `json:"trend_end"`
+	TrendChange     float64 `json:"trend_change"`
+	WeeklyRate      float64 `json:"weekly_rate"` // kg por semana
+	TDEE            float64 `json:"tdee"`
+	ConfidenceScore float64 `json:"confidence_score"` // 0 a 1
+	Confidence      string  `json:"confidence"`
+}
+
+// EstimateAdaptiveTDEE estima o gasto real nos days dias encerrados ontem (o
+// dia atual ainda não terminou). Dias sem registro ou com ingestão muito
+// abaixo da mediana ficam fora da média e reduzem a confiança, assim como
+// poucas pesagens no período.
+func EstimateAdaptiveTDEE(db *gorm.DB, u User, days int) (AdaptiveTDEE, error) {
+	loc := u.Location()
+	now := time.Now().In(loc)
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, -1)
+	from := to.AddDate(0, 0, -(days - 1))
+	result := AdaptiveTDEE{From: from.Format(DateLayout), To: to.Format(DateLayout), Days: days}
+
+	totals, err := SummaryTotals(db, u.ID, result.From, result.To)
+	if err != nil {
+		return result, err
+	}
+	var intakes []float64
+	for _, total := range totals {
+		if total.Calories > 0 {
+			intakes = append(intakes, total.Calories)
+		}
+	}
+	result.LoggedDays = len(intakes)
+	if len(intakes) == 0 {
+		return result, ErrInsufficientAdaptiveData
+	}
+
+	sort.Float64s(intakes)
+	median := intakes[len(intakes)/2]
+	if len(intakes)%2 == 0 {
+		median = (intakes[len(intakes)/2-1] + median) / 2
+	}
+	var sum float64
+	for _, intake := range intakes {
+		if intake >= median*adaptiveIncompleteDayRatio {
+			sum += intake
+			result.CompleteDays++
+		}
+	}
+	result.AverageIntake = math.Round(sum / float64(result.CompleteDays))
+
+	// A tendência começa antes do período para já estar estabilizada em from
+	var entries []WeightEntry
+	if err := db.Where("user_id = ? AND measured_at < ?", u.ID, to.AddDate(0, 0, 1)).
+		Where("measured_at >= ?", from.AddDate(0, 0, -60)).
+		Order("measured_at, id").Find(&entries).Error; err != nil {
+		return result, err
+	}
+	start, end, weighInDays, span := trendWindow(WeightTrend(entries, loc), result.From)
+	result.WeighInDays = weighInDays
+	if start == nil || result.CompleteDays < adaptiveMinCompleteDays || span < adaptiveMinTrendSpan {
+		return result, ErrInsufficientAdaptiveData
+	}
+
+	result.TrendStart, result.TrendEnd = start.Trend, end.Trend
+	result.TrendChange = math.Round((end.Trend-start.Trend)*100) / 100
+	dailyChange := (end.Trend - start.Trend) / span
+	result.WeeklyRate = math.Round(dailyChange*7*100) / 100
+	result.TDEE = math.Round(result.AverageIntake - dailyChange*KcalPerKgBodyWeight)
+
+	// Confiança: cobertura dos registros de refeições vezes a das pesagens
+	intakeCoverage := float64(result.CompleteDays) / float64(days)
+	weighCoverage := math.Min(1, float64(result.WeighInDays)/(float64(days)/7*adaptiveWeighingsPerWeek))
+	result.ConfidenceScore = math.Round(intakeCoverage*weighCoverage*100) / 100
+	switch {
+	case result.ConfidenceScore >= 0.75:
+		result.Confidence = ConfidenceHigh
+	case result.ConfidenceScore >= 0.5:
+		result.Confidence = ConfidenceMedium
+	default:
+		result.Confidence = ConfidenceLow
+	}
+	return result, nil
+}
+
+// trendWindow escolhe os pontos da tendência que medem a variação do peso no
+// período iniciado em from e quantos dias do período têm pesagem. A variação
+// é medida a partir de from, como a ingestão: sem pesagem nova entre o último
+// ponto anterior e from, a tendência em from é a daquele ponto; sem ponto
+// anterior, vale o primeiro do período. span é o intervalo em dias.
+func trendWindow(trend []WeightTrendPoint, from string) (start, end *WeightTrendPoint, weighInDays int, span float64) {
+	for i := range trend {
+		point := &trend[i]
+		if point.Date >= from {
+			weighInDays++
+		}
+		if start == nil || point.Date <= from {
+			start = point
+		}
+		end = point
+	}
+	if start == nil {
+		return nil, nil, 0, 0
+	}
+	startDate, _ := time.Parse(DateLayout, max(start.Date, from))
+	endDate, _ := time.Parse(DateLayout, end.Date)
+	return start, end, weighInDays, endDate.Sub(startDate).Hours() / 24
+}
+
+// ApplyAdaptiveTDEE carrega em AdaptiveTDEE o gasto estimado pelo balanço
+// energético quando o usuário optou por ele e a confiança não é baixa
+func (u *User) ApplyAdaptiveTDEE(db *gorm.DB) error {
+	if !u.UseAdaptiveTDEE {
+		return nil
+	}
+	estimate, err := EstimateAdaptiveTDEE(db, *u, AdaptiveTDEEWindowDays)
+	if errors.Is(err, ErrInsufficientAdaptiveData) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if estimate.Confidence != ConfidenceLow && estimate.TDEE > 0 {
+		u.AdaptiveTDEE = &estimate.TDEE
+	}
+	return nil
+}
+
+// ApplyEnergyData carrega os dados usados no cálculo do gasto além do perfil:
+// o percentual de gordura das medidas e o gasto adaptativo
+func (u *User) ApplyEnergyData(db *gorm.DB) error {
+	if err := u.ApplyBodyFat(db); err != nil {
+		return err
+	}
+	return u.ApplyAdaptiveTDEE(db)
+}
@@ -0,0 +1,55 @@
+package models
+
+import "testing"
+
+func TestTrendWindow(t *testing.T) {
+	tests := []struct {
+		name        string
+		trend       []WeightTrendPoint
+		wantStart   string
+		wantEnd     string
+		wantWeighIn int
+		wantSpan    float64
+	}{
+		{
+			// O ponto de 50 dias antes vale em from: o intervalo começa em from
+			name: "ponto anterior distante",
+			trend: []WeightTrendPoint{
+				{Date: "2026-01-10", Trend: 80},
+				{Date: "2026-03-05", Trend: 79.5},
+				{Date: "2026-03-28", Trend: 79},
+			},
+			wantStart: "2026-01-10", wantEnd: "2026-03-28", wantWeighIn: 2, wantSpan: 27,
+		},
+		{
+			name: "ponto em from",
+			trend: []WeightTrendPoint{
+				{Date: "2026-02-20", Trend: 81},
+				{Date: "2026-03-01", Trend: 80},
+				{Date: "2026-03-15", Trend: 79.6},
+			},
+			wantStart: "2026-03-01", wantEnd: "2026-03-15", wantWeighIn: 2, wantSpan: 14,
+		},
+		{
+			name: "sem ponto anterior",
+			trend: []WeightTrendPoint{
+				{Date: "2026-03-04", Trend: 80},
+				{Date: "2026-03-20", Trend: 79.4},
+			},
+			wantStart: "2026-03-04", wantEnd: "2026-03-20", wantWeighIn: 2, wantSpan: 16,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, weighIn, span := trendWindow(tt.trend, "2026-03-01")
+			if start == nil || start.Date != tt.wantStart || end.Date != tt.wantEnd ||
+				weighIn != tt.wantWeighIn || span != tt.wantSpan {
+				t.Errorf("trendWindow = %+v, %+v, %d, %v", start, end, weighIn, span)
+			}
+		})
+	}
+
+	if start, _, _, _ := trendWindow(nil, "2026-03-01"); start != nil {
+		t.Errorf("sem pesagens, start = %+v", start)
+	}
+}
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Os resumos (refeição, dia e períodos) somam os nutrientes gravados nos itens
+// com consultas agrupadas no banco, em vez de buscar refeição por refeição e
+// item por item. Cada função abaixo faz uma única consulta.
+
+// NutrientFilter restringe as refeições consideradas na agregação. Campos
+// zerados não filtram; UserID deve sempre ser informado nas rotas do usuário.
+type NutrientFilter struct {
+	UserID uint
+	MealID uint
+	From   time.Time // Inclusive
+	To     time.Time // Exclusive
+}
+
+// NutrientAggregate é o resultado de uma agregação
+type NutrientAggregate struct {
+	Nutrients
+	QuickAddCalories float64 `json:"quick_add_calories"`
+	Meals            int64   `json:"meals"`
+	Items            int64   `json:"items"`
+}
+
+// nutrientSums monta as colunas de soma de meal_items, uma por nutriente
+func nutrientSums() string {
+	sums := make([]string, len(nutrientColumns))
+	for i, column := range nutrientColumns {
+		sums[i] = "COALESCE(SUM(meal_items." + column + "), 0) AS " + column
+	}
+	return strings.Join(sums, ", ")
+}
+
+// quickAddSum soma as calorias dos registros rápidos
+const quickAddSum = "COALESCE(SUM(CASE WHEN meal_items.quick_add THEN meal_items.calories ELSE 0 END), 0) AS quick_add_calories"
+
+// mealScope aplica o filtro sobre a tabela meals
+func (f NutrientFilter) mealScope(db *gorm.DB) *gorm.DB {
+	if f.UserID != 0 {
+		db = db.Where("meals.user_id = ?", f.UserID)
+	}
+	if f.MealID != 0 {
+		db = db.Where("meals.id = ?", f.MealID)
+	}
+	if !f.From.IsZero() {
+		db = db.Where("meals.created_at >= ?", f.From)
+	}
+	if !f.To.IsZero() {
+		db = db.Where("meals.created_at < ?", f.To)
+	}
+	return db
+}
+
+// AggregateNutrients soma os itens das refeições do filtro. Refeições sem
+// itens entram na contagem de refeições com nutrientes zerados.
+func AggregateNutrients(db *gorm.DB, filter NutrientFilter) (NutrientAggregate, error) {
+	var result NutrientAggregate
+	err := db.Table("meals").
+		Select(nutrientSums() + ", " + quickAddSum + ", " +
+			"COUNT(DISTINCT meals.id) AS meals, COUNT(meal_items.id) AS items").
+		Joins("LEFT JOIN meal_items ON meal_items.meal_id = meals.id").
+		Scopes(filter.mealScope).
+		Scan(&result).Error
+	return result, err
+}
+
+// DailyAggregates agrega as refeições do filtro por dia, no fuso informado.
+// As somas são feitas por refeição no banco e agrupadas por dia aqui, para
+// respeitar o fuso do usuário sem depender de funções de data do banco.
+func DailyAggregates(db *gorm.DB, filter NutrientFilter, loc *time.Location) (map[string]NutrientAggregate, error) {
+	var rows []struct {
+		CreatedAt time.Time
+		NutrientAggregate
+	}
+	err := db.Table("meals").
+		Select("meals.created_at, " + nutrientSums() + ", " + quickAddSum + ", " +
+			"1 AS meals, COUNT(meal_items.id) AS items").
+		Joins("LEFT JOIN meal_items ON meal_items.meal_id = meals.id").
+		Scopes(filter.mealScope).
+		Group("meals.id, meals.created_at").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	days := make(map[string]NutrientAggregate)
+	for _, row := range rows {
+		day := row.CreatedAt.In(loc).Format(DateLayout)
+		total := days[day]
+		total.Nutrients = total.Add(row.Nutrients)
+		total.QuickAddCalories += row.QuickAddCalories
+		total.Meals += row.Meals
+		total.Items += row.Items
+		days[day] = total
+	}
+	return days, nil
+}
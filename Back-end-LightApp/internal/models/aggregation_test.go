@@ -0,0 +1,177 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// stubConnector é um banco falso: conta as instruções recebidas e responde
+// toda consulta com as mesmas linhas
+type stubConnector struct {
+	columns    []string
+	rows       [][]driver.Value
+	statements []string
+}
+
+func (s *stubConnector) Connect(context.Context) (driver.Conn, error) { return stubConn{s}, nil }
+func (s *stubConnector) Driver() driver.Driver                        { return nil }
+
+type stubConn struct{ db *stubConnector }
+
+func (c stubConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepare não suportado")
+}
+func (c stubConn) Close() error              { return nil }
+func (c stubConn) Begin() (driver.Tx, error) { return stubTx{}, nil }
+
+type stubTx struct{}
+
+func (stubTx) Commit() error   { return nil }
+func (stubTx) Rollback() error { return nil }
+
+func (c stubConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	c.db.statements = append(c.db.statements, query)
+	return &stubRows{columns: c.db.columns, rows: c.db.rows}, nil
+}
+
+func (c stubConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.db.statements = append(c.db.statements, query)
+	return driver.RowsAffected(0), nil
+}
+
+type stubRows struct {
+	columns []string
+	rows    [][]driver.Value
+	next    int
+}
+
+func (r *stubRows) Columns() []string { return r.columns }
+func (r *stubRows) Close() error      { return nil }
+
+func (r *stubRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.next])
+	r.next++
+	return nil
+}
+
+func openStub(t *testing.T, stub *stubConnector) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sql.OpenDB(stub)}), &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               logger.Discard,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestAggregateNutrientsSingleQuery(t *testing.T) {
+	for _, items := range []int64{0, 1, 50, 1000} {
+		t.Run(fmt.Sprint(items, " itens"), func(t *testing.T) {
+			stub := &stubConnector{
+				columns: []string{"calories", "protein", "quick_add_calories", "meals", "items"},
+				rows:    [][]driver.Value{{float64(items) * 100, float64(items) * 5, 0.0, int64(3), items}},
+			}
+			db := openStub(t, stub)
+
+			day := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+			filters := map[string]NutrientFilter{
+				"refeição": {UserID: 1, MealID: 7},
+				"dia":      {UserID: 1, From: day, To: day.AddDate(0, 0, 1)},
+			}
+			for name, filter := range filters {
+				stub.statements = nil
+				total, err := AggregateNutrients(db, filter)
+				if err != nil {
+					t.Fatalf("%s: %v", name, err)
+				}
+				if len(stub.statements) != 1 {
+					t.Fatalf("%s: %d consultas, esperada 1: %q", name, len(stub.statements), stub.statements)
+				}
+				if !strings.Contains(stub.statements[0], "LEFT JOIN meal_items") {
+					t.Errorf("%s: consulta sem junção dos itens: %s", name, stub.statements[0])
+				}
+				if total.Items != items || total.Calories != float64(items)*100 || total.Meals != 3 {
+					t.Errorf("%s: total = %+v", name, total)
+				}
+			}
+		})
+	}
+}
+
+func TestDailyAggregatesSingleQuery(t *testing.T) {
+	loc := time.FixedZone("BRT", -3*60*60)
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, loc)
+
+	for _, meals := range []int{0, 1, 30, 500} {
+		t.Run(fmt.Sprint(meals, " refeições"), func(t *testing.T) {
+			stub := &stubConnector{columns: []string{"created_at", "calories", "quick_add_calories", "meals", "items"}}
+			days := 10
+			for i := 0; i < meals; i++ {
+				at := start.AddDate(0, 0, i%days).UTC()
+				stub.rows = append(stub.rows, []driver.Value{at, 200.0, 0.0, int64(1), int64(4)})
+			}
+			db := openStub(t, stub)
+
+			result, err := DailyAggregates(db, NutrientFilter{UserID: 1, From: start, To: start.AddDate(0, 0, days)}, loc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(stub.statements) != 1 {
+				t.Fatalf("%d consultas, esperada 1: %q", len(stub.statements), stub.statements)
+			}
+
+			var totalMeals, totalItems int64
+			for _, day := range result {
+				totalMeals += day.Meals
+				totalItems += day.Items
+			}
+			if totalMeals != int64(meals) || totalItems != int64(meals)*4 {
+				t.Errorf("refeições = %d, itens = %d", totalMeals, totalItems)
+			}
+			if want := min(meals, days); len(result) != want {
+				t.Errorf("%d dias, esperados %d", len(result), want)
+			}
+		})
+	}
+}
+
+func TestSummaryTotalsSingleQuery(t *testing.T) {
+	for _, days := range []int{0, 7, 366} {
+		t.Run(fmt.Sprint(days, " dias"), func(t *testing.T) {
+			stub := &stubConnector{columns: []string{"user_id", "date", "calories", "items"}}
+			from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+			for i := 0; i < days; i++ {
+				date := from.AddDate(0, 0, i).Format(DateLayout)
+				stub.rows = append(stub.rows, []driver.Value{int64(1), date, 1800.0, int64(6)})
+			}
+			db := openStub(t, stub)
+
+			totals, err := SummaryTotals(db, 1, from.Format(DateLayout), from.AddDate(0, 0, MaxSummaryDays).Format(DateLayout))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(stub.statements) != 1 {
+				t.Fatalf("%d consultas, esperada 1: %q", len(stub.statements), stub.statements)
+			}
+			if len(totals) != days {
+				t.Errorf("%d dias, esperados %d", len(totals), days)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AuditLog registra operações administrativas que alteram dados de usuários
+type AuditLog struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ActorID   uint      `gorm:"index" json:"actor_id"`
+	Action    string    `gorm:"index" json:"action"`
+	Details   string    `json:"details"` // JSON com os parâmetros e o resultado da operação
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func MigrateAuditLog(db *gorm.DB) error {
+	return db.AutoMigrate(&AuditLog{})
+}
+
+// RecordAudit grava uma entrada de auditoria com os detalhes serializados em JSON
+func RecordAudit(db *gorm.DB, actorID uint, action string, details interface{}) error {
+	payload, err := json.Marshal(details)
+	if err != nil {
+		return err
+	}
+	return db.Create(&AuditLog{ActorID: actorID, Action: action, Details: string(payload)}).Error
+}
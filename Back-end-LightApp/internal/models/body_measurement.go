@@ -0,0 +1,269 @@
+package models
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Por quanto tempo uma medida continua valendo para as métricas atuais e a TMB
+const BodyMeasurementValidity = 180 * 24 * time.Hour
+
+// BodyMeasurement registra circunferências (cm) e percentual de gordura.
+// Os campos são opcionais: cada registro pode trazer só parte das medidas.
+type BodyMeasurement struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	UserID     uint      `gorm:"index:idx_measurement_user_measured" json:"user_id"`
+	MeasuredAt time.Time `gorm:"index:idx_measurement_user_measured" json:"measured_at"`
+	Waist      *float64  `json:"waist"`    // Cintura, na altura do umbigo
+	Hip        *float64  `json:"hip"`      // Quadril, na maior circunferência
+	Neck       *float64  `json:"neck"`     // Pescoço, abaixo da laringe
+	BodyFat    *float64  `json:"body_fat"` // %, medido (bioimpedância, dobras, DEXA)
+	Note       string    `json:"note"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func MigrateBodyMeasurement(db *gorm.DB) error {
+	return db.AutoMigrate(&BodyMeasurement{})
+}
+
+// Validate confere se há ao menos uma medida e se os valores são plausíveis
+func (m BodyMeasurement) Validate() error {
+	if m.Waist == nil && m.Hip == nil && m.Neck == nil && m.BodyFat == nil {
+		return errors.New("Informe ao menos uma medida")
+	}
+	ranges := []struct {
+		value    *float64
+		min, max float64
+		message  string
+	}{
+		{m.Waist, 40, 250, "Cintura inválida (entre 40 e 250 cm)"},
+		{m.Hip, 50, 250, "Quadril inválido (entre 50 e 250 cm)"},
+		{m.Neck, 20, 80, "Pescoço inválido (entre 20 e 80 cm)"},
+		{m.BodyFat, 2, 70, "Percentual de gordura inválido (entre 2% e 70%)"},
+	}
+	for _, r := range ranges {
+		if r.value != nil && (*r.value < r.min || *r.value > r.max) {
+			return errors.New(r.message)
+		}
+	}
+	return nil
+}
+
+// CurrentMeasurement combina a medida mais recente de cada campo registrada
+// desde since. ok é falso quando não há medidas no período.
+func CurrentMeasurement(db *gorm.DB, userID uint, since time.Time) (BodyMeasurement, bool, error) {
+	var measurements []BodyMeasurement
+	if err := db.Where("user_id = ? AND measured_at >= ?", userID, since).
+		Order("measured_at DESC, id DESC").Find(&measurements).Error; err != nil {
+		return BodyMeasurement{}, false, err
+	}
+	if len(measurements) == 0 {
+		return BodyMeasurement{}, false, nil
+	}
+
+	current := BodyMeasurement{UserID: userID, MeasuredAt: measurements[0].MeasuredAt}
+	for _, m := range measurements {
+		if current.Waist == nil {
+			current.Waist = m.Waist
+		}
+		if current.Hip == nil {
+			current.Hip = m.Hip
+		}
+		if current.Neck == nil {
+			current.Neck = m.Neck
+		}
+		if current.BodyFat == nil {
+			current.BodyFat = m.BodyFat
+		}
+	}
+	return current, true, nil
+}
+
+// Origem do percentual de gordura usado nas métricas
+const (
+	BodyFatSourceMeasured = "measured"
+	BodyFatSourceNavy     = "navy"
+)
+
+// RatedMetric é um valor com a categoria de risco correspondente
+type RatedMetric struct {
+	Value    float64 `json:"value"`
+	Category string  `json:"category,omitempty"` // Vazio quando o corte depende do sexo e ele não foi informado
+}
+
+// BodyComposition reúne as métricas derivadas das medidas, do peso e da
+// altura. Métricas sem os dados necessários ficam nulas; sem o sexo no
+// perfil, a estimativa da Marinha e as categorias por sexo ficam de fora.
+type BodyComposition struct {
+	Waist         *RatedMetric `json:"waist,omitempty"`           // Circunferência da cintura (OMS)
+	WaistToHip    *RatedMetric `json:"waist_to_hip,omitempty"`    // Relação cintura-quadril (OMS)
+	WaistToHeight *RatedMetric `json:"waist_to_height,omitempty"` // Relação cintura-altura (Ashwell)
+	NavyBodyFat   *float64     `json:"navy_body_fat,omitempty"`   // Estimativa da Marinha dos EUA, %
+	BodyFat       *RatedMetric `json:"body_fat,omitempty"`        // Medido ou, na falta, o da Marinha (ACE)
+	BodyFatSource string       `json:"body_fat_source,omitempty"`
+	LeanMass      *float64     `json:"lean_mass,omitempty"` // kg
+	FatMass       *float64     `json:"fat_mass,omitempty"`  // kg
+}
+
+// BodyComposition calcula as métricas derivadas de uma medida
+func (u User) BodyComposition(m BodyMeasurement) BodyComposition {
+	var result BodyComposition
+	male, sexKnown := u.isMale(), u.Sex != ""
+	rate := func(value float64, category func(float64, bool) string) *RatedMetric {
+		metric := &RatedMetric{Value: value}
+		if sexKnown {
+			metric.Category = category(value, male)
+		}
+		return metric
+	}
+
+	if m.Waist != nil {
+		result.Waist = rate(*m.Waist, waistCategory)
+		if m.Hip != nil {
+			result.WaistToHip = rate(round2(*m.Waist / *m.Hip), waistToHipCategory)
+		}
+		if u.Height > 0 {
+			ratio := round2(*m.Waist / u.Height)
+			result.WaistToHeight = &RatedMetric{Value: ratio, Category: waistToHeightCategory(ratio)}
+		}
+	}
+
+	if navy, ok := navyBodyFat(m, u.Height, male); ok && sexKnown {
+		result.NavyBodyFat = &navy
+	}
+
+	switch {
+	case m.BodyFat != nil:
+		result.BodyFat = rate(*m.BodyFat, bodyFatCategory)
+		result.BodyFatSource = BodyFatSourceMeasured
+	case result.NavyBodyFat != nil:
+		result.BodyFat = rate(*result.NavyBodyFat, bodyFatCategory)
+		result.BodyFatSource = BodyFatSourceNavy
+	}
+
+	if result.BodyFat != nil && u.Weight > 0 {
+		fat := round2(u.Weight * result.BodyFat.Value / 100)
+		lean := round2(u.Weight - fat)
+		result.FatMass, result.LeanMass = &fat, &lean
+	}
+	return result
+}
+
+// ApplyBodyFat carrega em BodyFat o percentual de gordura atual (medido ou
+// estimado pelas circunferências), usado pela equação de Katch-McArdle
+func (u *User) ApplyBodyFat(db *gorm.DB) error {
+	current, ok, err := CurrentMeasurement(db, u.ID, time.Now().Add(-BodyMeasurementValidity))
+	if err != nil || !ok {
+		return err
+	}
+	if composition := u.BodyComposition(current); composition.BodyFat != nil {
+		value := composition.BodyFat.Value
+		u.BodyFat = &value
+	}
+	return nil
+}
+
+// navyBodyFat aplica a fórmula da Marinha dos EUA (versão métrica, em cm)
+func navyBodyFat(m BodyMeasurement, height float64, male bool) (float64, bool) {
+	if m.Waist == nil || m.Neck == nil || height <= 0 {
+		return 0, false
+	}
+	var fat float64
+	if male {
+		if *m.Waist <= *m.Neck {
+			return 0, false
+		}
+		fat = 495/(1.0324-0.19077*math.Log10(*m.Waist-*m.Neck)+0.15456*math.Log10(height)) - 450
+	} else {
+		if m.Hip == nil || *m.Waist+*m.Hip <= *m.Neck {
+			return 0, false
+		}
+		fat = 495/(1.29579-0.35004*math.Log10(*m.Waist+*m.Hip-*m.Neck)+0.22100*math.Log10(height)) - 450
+	}
+	if fat <= 0 || fat >= 75 {
+		return 0, false
+	}
+	return round2(fat), true
+}
+
+// Categorias de risco
+const (
+	RiskLow                    = "low"
+	RiskHealthy                = "healthy"
+	RiskIncreased              = "increased"
+	RiskSubstantiallyIncreased = "substantially_increased"
+	RiskHigh                   = "high"
+)
+
+// waistCategory segue os pontos de corte da OMS (94/102 cm e 80/88 cm)
+func waistCategory(waist float64, male bool) string {
+	increased, substantial := 80.0, 88.0
+	if male {
+		increased, substantial = 94, 102
+	}
+	switch {
+	case waist >= substantial:
+		return RiskSubstantiallyIncreased
+	case waist >= increased:
+		return RiskIncreased
+	}
+	return RiskLow
+}
+
+// waistToHipCategory segue a OMS: risco aumentado a partir de 0,90 (homens) e 0,85 (mulheres)
+func waistToHipCategory(ratio float64, male bool) string {
+	limit := 0.85
+	if male {
+		limit = 0.90
+	}
+	if ratio >= limit {
+		return RiskSubstantiallyIncreased
+	}
+	return RiskLow
+}
+
+// waistToHeightCategory segue Ashwell: a cintura deve ficar abaixo da metade da altura
+func waistToHeightCategory(ratio float64) string {
+	switch {
+	case ratio >= 0.6:
+		return RiskHigh
+	case ratio >= 0.5:
+		return RiskIncreased
+	case ratio >= 0.4:
+		return RiskHealthy
+	}
+	return RiskLow
+}
+
+// Categorias de percentual de gordura do American Council on Exercise
+const (
+	BodyFatBelowEssential = "below_essential"
+	BodyFatEssential      = "essential"
+	BodyFatAthletes       = "athletes"
+	BodyFatFitness        = "fitness"
+	BodyFatAverage        = "average"
+	BodyFatObese          = "obese"
+)
+
+func bodyFatCategory(percent float64, male bool) string {
+	limits := []float64{10, 14, 21, 25, 32} // Mulheres
+	if male {
+		limits = []float64{2, 6, 14, 18, 25}
+	}
+	categories := []string{BodyFatEssential, BodyFatAthletes, BodyFatFitness, BodyFatAverage, BodyFatObese}
+	category := BodyFatBelowEssential
+	for i, limit := range limits {
+		if percent >= limit {
+			category = categories[i]
+		}
+	}
+	return category
+}
+
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
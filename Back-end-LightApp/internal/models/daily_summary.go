@@ -1,8 +1,168 @@
 package models
 
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DailySummary guarda os totais de um usuário em um dia do fuso dele. A linha
+// é atualizada na mesma transação de qualquer alteração em refeições ou
+// itens, e os relatórios por período leem daqui em vez dos itens.
 type DailySummary struct {
-	Calories float64 `json:"calories"`
-	Proteins float64 `json:"proteins"`
-	Carbs    float64 `json:"carbs"`
-	Fat      float64 `json:"fat"`
+	ID     uint   `gorm:"primaryKey" json:"-"`
+	UserID uint   `gorm:"uniqueIndex:idx_daily_summary_user_date" json:"user_id"`
+	Date   string `gorm:"size:10;uniqueIndex:idx_daily_summary_user_date" json:"date"` // AAAA-MM-DD
+	Nutrients
+	QuickAddCalories float64   `json:"quick_add_calories"`
+	Meals            int64     `json:"meals"`
+	Items            int64     `json:"items"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+func MigrateDailySummary(db *gorm.DB) error {
+	return db.AutoMigrate(&DailySummary{})
+}
+
+// UserLocation busca apenas o fuso do usuário
+func UserLocation(db *gorm.DB, userID uint) (*time.Location, error) {
+	var user User
+	if err := db.Select("id", "time_zone").First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+	return user.Location(), nil
+}
+
+// lockUserLocation busca o fuso do usuário travando a linha dele até o fim da
+// transação, para que dois recálculos do mesmo usuário não se sobreponham e o
+// último a gravar não descarte os itens do outro. NO KEY UPDATE não bloqueia
+// inserções que só referenciam o usuário.
+func lockUserLocation(db *gorm.DB, userID uint) (*time.Location, error) {
+	var user User
+	if err := db.Clauses(clause.Locking{Strength: "NO KEY UPDATE"}).
+		Select("id", "time_zone").First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+	return user.Location(), nil
+}
+
+// RefreshDailySummary recalcula o dia (no fuso do usuário) que contém at.
+// Deve ser chamada na mesma transação que alterou refeições ou itens.
+func RefreshDailySummary(db *gorm.DB, userID uint, at time.Time) error {
+	loc, err := lockUserLocation(db, userID)
+	if err != nil {
+		return err
+	}
+	local := at.In(loc)
+	start := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	date := start.Format(DateLayout)
+
+	total, err := AggregateNutrients(db, NutrientFilter{UserID: userID, From: start, To: start.AddDate(0, 0, 1)})
+	if err != nil {
+		return err
+	}
+	if total.Meals == 0 {
+		return db.Where("user_id = ? AND date = ?", userID, date).Delete(&DailySummary{}).Error
+	}
+	return saveDailySummaries(db, []DailySummary{newDailySummary(userID, date, total)})
+}
+
+// RebuildDailySummaries refaz os totais a partir dos itens entre from e to
+// (AAAA-MM-DD, inclusive; vazios não limitam). Sem usuários informados,
+// refaz todos os que têm refeições. Retorna quantos dias foram gravados.
+func RebuildDailySummaries(db *gorm.DB, userIDs []uint, from, to string) (int, error) {
+	if userIDs == nil {
+		if err := db.Model(&Meal{}).Distinct().Pluck("user_id", &userIDs).Error; err != nil {
+			return 0, err
+		}
+	}
+
+	rebuilt := 0
+	for _, userID := range userIDs {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			loc, err := lockUserLocation(tx, userID)
+			if err != nil {
+				return err
+			}
+
+			filter := NutrientFilter{UserID: userID}
+			stale := tx.Where("user_id = ?", userID)
+			if from != "" {
+				start, err := time.ParseInLocation(DateLayout, from, loc)
+				if err != nil {
+					return err
+				}
+				filter.From = start
+				stale = stale.Where("date >= ?", from)
+			}
+			if to != "" {
+				end, err := time.ParseInLocation(DateLayout, to, loc)
+				if err != nil {
+					return err
+				}
+				filter.To = end.AddDate(0, 0, 1)
+				stale = stale.Where("date <= ?", to)
+			}
+
+			days, err := DailyAggregates(tx, filter, loc)
+			if err != nil {
+				return err
+			}
+			if err := stale.Delete(&DailySummary{}).Error; err != nil {
+				return err
+			}
+
+			summaries := make([]DailySummary, 0, len(days))
+			for date, total := range days {
+				summaries = append(summaries, newDailySummary(userID, date, total))
+			}
+			if err := saveDailySummaries(tx, summaries); err != nil {
+				return err
+			}
+			rebuilt += len(summaries)
+			return nil
+		})
+		if err != nil {
+			return rebuilt, err
+		}
+	}
+	return rebuilt, nil
+}
+
+// SummaryTotals lê os totais gravados entre from e to (AAAA-MM-DD, inclusive),
+// apenas dos dias com itens registrados
+func SummaryTotals(db *gorm.DB, userID uint, from, to string) (map[string]Nutrients, error) {
+	var summaries []DailySummary
+	if err := db.Where("user_id = ? AND date >= ? AND date <= ? AND items > 0", userID, from, to).
+		Find(&summaries).Error; err != nil {
+		return nil, err
+	}
+	totals := make(map[string]Nutrients, len(summaries))
+	for _, summary := range summaries {
+		totals[summary.Date] = summary.Nutrients
+	}
+	return totals, nil
+}
+
+func newDailySummary(userID uint, date string, total NutrientAggregate) DailySummary {
+	return DailySummary{
+		UserID:           userID,
+		Date:             date,
+		Nutrients:        total.Nutrients,
+		QuickAddCalories: total.QuickAddCalories,
+		Meals:            total.Meals,
+		Items:            total.Items,
+	}
+}
+
+func saveDailySummaries(db *gorm.DB, summaries []DailySummary) error {
+	if len(summaries) == 0 {
+		return nil
+	}
+	columns := append([]string{"quick_add_calories", "meals", "items", "updated_at"}, nutrientColumns...)
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns(columns),
+	}).CreateInBatches(summaries, 500).Error
 }
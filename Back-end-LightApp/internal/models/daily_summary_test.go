@@ -0,0 +1,23 @@
+package models
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRefreshDailySummaryLocksUser(t *testing.T) {
+	stub := &stubConnector{
+		columns: []string{"id", "time_zone"},
+		rows:    [][]driver.Value{{int64(1), "America/Sao_Paulo"}},
+	}
+	db := openStub(t, stub)
+
+	if err := RefreshDailySummary(db, 1, time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	if len(stub.statements) == 0 || !strings.Contains(stub.statements[0], "FOR NO KEY UPDATE") {
+		t.Fatalf("o usuário deve ser travado antes do recálculo: %q", stub.statements)
+	}
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DiaryRow é uma linha da exportação do diário: um item de refeição com os
+// dados da refeição e o nome do alimento
+type DiaryRow struct {
+	MealID    uint
+	CreatedAt time.Time // Horário da refeição
+	MealType  string
+	FoodID    *uint // Número do alimento na TACO; nulo em registros rápidos
+	Food      string
+	QuickAdd  bool
+	Quantity  float64
+	Unit      string
+	Amount    float64 // Gramas
+	Nutrients
+}
+
+// StreamDiary percorre os itens das refeições do usuário entre from (inclusive)
+// e to (exclusive) em ordem cronológica, sem carregar o período em memória
+func StreamDiary(db *gorm.DB, userID uint, from, to time.Time, fn func(DiaryRow) error) error {
+	columns := "meals.id AS meal_id, meals.created_at, meals.meal_type, meal_items.food_id, " +
+		"COALESCE(foods.name, meal_items.label) AS food, meal_items.quick_add, " +
+		"meal_items.quantity, meal_items.unit, meal_items.amount"
+	for _, column := range nutrientColumns {
+		columns += ", meal_items." + column
+	}
+
+	rows, err := db.Table("meal_items").
+		Select(columns).
+		Joins("JOIN meals ON meals.id = meal_items.meal_id").
+		Joins("LEFT JOIN foods ON foods.id = meal_items.food_id").
+		Where("meals.user_id = ? AND meals.created_at >= ? AND meals.created_at < ?", userID, from, to).
+		Order("meals.created_at, meals.id, meal_items.id").
+		Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row DiaryRow
+		if err := db.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,307 @@
+package models
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Sexo usado nas equações e nas referências (independente de Gender, que é a
+// identidade de gênero informada pelo usuário)
+const (
+	SexMale   = "male"
+	SexFemale = "female"
+)
+
+// Equações de TMB
+const (
+	EquationMifflinStJeor  = "mifflin_st_jeor"
+	EquationHarrisBenedict = "harris_benedict_revised"
+	EquationKatchMcArdle   = "katch_mcardle"
+	EquationFAOWHO         = "fao_who"
+)
+
+var (
+	ErrInvalidSex            = errors.New("Sexo inválido (use male ou female)")
+	ErrInvalidActivity       = errors.New("Nível de atividade inválido (use um dos presets)")
+	ErrInvalidEnergyEquation = errors.New("Equação de TMB inválida")
+	ErrBodyFatRequired       = errors.New("A equação de Katch-McArdle precisa do percentual de gordura (registre uma medida corporal)")
+)
+
+// EnergyInputs são os dados do perfil usados no cálculo
+type EnergyInputs struct {
+	Sex                string   `json:"sex,omitempty"`
+	Age                int      `json:"age,omitempty"`
+	Weight             float64  `json:"weight"`
+	Height             float64  `json:"height,omitempty"`
+	BodyFat            *float64 `json:"body_fat,omitempty"`
+	LeanMass           float64  `json:"lean_mass,omitempty"`
+	Activity           string   `json:"activity"`
+	ActivityMultiplier float64  `json:"activity_multiplier"`
+}
+
+// EnergyEquation descreve uma equação de TMB e os dados que ela exige
+type EnergyEquation struct {
+	Key             string `json:"key"`
+	Name            string `json:"name"`
+	Reference       string `json:"reference"`
+	RequiresBodyFat bool   `json:"requires_body_fat"`
+	RequiresSex     bool   `json:"requires_sex"`
+	RequiresHeight  bool   `json:"requires_height"`
+
+	bmr func(in EnergyInputs) float64
+}
+
+// EnergyEquations são as equações disponíveis para o usuário ou o nutricionista
+var EnergyEquations = map[string]EnergyEquation{
+	EquationMifflinStJeor: {
+		Name:        "Mifflin-St Jeor",
+		Reference:   "Mifflin et al., 1990",
+		RequiresSex: true, RequiresHeight: true,
+		bmr: func(in EnergyInputs) float64 {
+			bmr := 10*in.Weight + 6.25*in.Height - 5*float64(in.Age)
+			if in.Sex == SexMale {
+				return bmr + 5
+			}
+			return bmr - 161
+		},
+	},
+	EquationHarrisBenedict: {
+		Name:        "Harris-Benedict revisada",
+		Reference:   "Roza e Shizgal, 1984",
+		RequiresSex: true, RequiresHeight: true,
+		bmr: func(in EnergyInputs) float64 {
+			if in.Sex == SexMale {
+				return 88.362 + 13.397*in.Weight + 4.799*in.Height - 5.677*float64(in.Age)
+			}
+			return 447.593 + 9.247*in.Weight + 3.098*in.Height - 4.330*float64(in.Age)
+		},
+	},
+	EquationKatchMcArdle: {
+		Name:            "Katch-McArdle",
+		Reference:       "Katch e McArdle, 1996",
+		RequiresBodyFat: true,
+		bmr: func(in EnergyInputs) float64 {
+			return 370 + 21.6*in.LeanMass
+		},
+	},
+	EquationFAOWHO: {
+		Name:        "FAO/OMS/UNU",
+		Reference:   "FAO/WHO/UNU, 1985 (Schofield)",
+		RequiresSex: true,
+		bmr:         faoWHOBMR,
+	},
+}
+
+// Coeficientes FAO/OMS por faixa etária: TMB = a × peso + b
+var faoWHOCoefficients = map[string][]struct {
+	maxAge int
+	a, b   float64
+}{
+	SexMale: {
+		{3, 60.9, -54}, {10, 22.7, 495}, {18, 17.5, 651},
+		{30, 15.3, 679}, {60, 11.6, 879}, {math.MaxInt, 13.5, 487},
+	},
+	SexFemale: {
+		{3, 61.0, -51}, {10, 22.5, 499}, {18, 12.2, 746},
+		{30, 14.7, 496}, {60, 8.7, 829}, {math.MaxInt, 10.5, 596},
+	},
+}
+
+func faoWHOBMR(in EnergyInputs) float64 {
+	for _, band := range faoWHOCoefficients[in.Sex] {
+		if in.Age < band.maxAge {
+			return band.a*in.Weight + band.b
+		}
+	}
+	return 0
+}
+
+// ActivityPreset é um nível de atividade com o fator aplicado à TMB
+type ActivityPreset struct {
+	Key        string  `json:"key"`
+	Name       string  `json:"name"`
+	Multiplier float64 `json:"multiplier"`
+}
+
+// ActivityPresets são os níveis de atividade aceitos, do menor ao maior fator
+var ActivityPresets = []ActivityPreset{
+	{"sedentary", "Sedentário (pouco ou nenhum exercício)", 1.2},
+	{"light", "Levemente ativo (1 a 3 dias por semana)", 1.375},
+	{"moderate", "Moderadamente ativo (3 a 5 dias por semana)", 1.55},
+	{"active", "Muito ativo (6 a 7 dias por semana)", 1.725},
+	{"very_active", "Extremamente ativo (treino intenso ou trabalho físico)", 1.9},
+}
+
+// FindActivityPreset procura o nível de atividade pela chave
+func FindActivityPreset(key string) (ActivityPreset, bool) {
+	for _, preset := range ActivityPresets {
+		if preset.Key == key {
+			return preset, true
+		}
+	}
+	return ActivityPreset{}, false
+}
+
+// nearestActivityPreset retorna o nível cujo fator mais se aproxima de multiplier
+func nearestActivityPreset(multiplier float64) ActivityPreset {
+	nearest := ActivityPresets[0]
+	for _, preset := range ActivityPresets[1:] {
+		if math.Abs(preset.Multiplier-multiplier) < math.Abs(nearest.Multiplier-multiplier) {
+			nearest = preset
+		}
+	}
+	return nearest
+}
+
+// legacyActivityPreset traduz a escala de 1 a 5 enviada pelo cadastro do app
+// (1 = sedentário, 5 = extremamente ativo), anterior aos presets
+func legacyActivityPreset(level float64) (ActivityPreset, bool) {
+	if level < 1 || level > float64(len(ActivityPresets)) || level != math.Trunc(level) {
+		return ActivityPreset{}, false
+	}
+	return ActivityPresets[int(level)-1], true
+}
+
+// legacyGenderSex são os valores de gênero do cadastro do app (M/F/Outro) e
+// dos perfis antigos que indicam o sexo
+var legacyGenderSex = map[string]string{
+	"m": SexMale, "male": SexMale, "masculino": SexMale,
+	"f": SexFemale, "female": SexFemale, "feminino": SexFemale,
+}
+
+// EnergyEquationList retorna as equações em ordem alfabética de chave
+func EnergyEquationList() []EnergyEquation {
+	list := make([]EnergyEquation, 0, len(EnergyEquations))
+	for key, equation := range EnergyEquations {
+		equation.Key = key
+		list = append(list, equation)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Key < list[j].Key })
+	return list
+}
+
+// NormalizeEnergyProfile valida sexo, equação e nível de atividade do perfil.
+// O nível pode vir pela chave (activity) ou pelo fator (activity_level), que
+// precisa ser o de um dos presets ou a escala de 1 a 5 do app; os dois campos
+// ficam coerentes. Sem sexo informado, ele vem do gênero quando é M ou F.
+func (u *User) NormalizeEnergyProfile() error {
+	if u.Sex == "" {
+		u.Sex = legacyGenderSex[strings.ToLower(strings.TrimSpace(u.Gender))]
+	}
+	if u.Sex != "" && u.Sex != SexMale && u.Sex != SexFemale {
+		return ErrInvalidSex
+	}
+	if _, ok := EnergyEquations[u.EnergyEquation]; u.EnergyEquation != "" && !ok {
+		return ErrInvalidEnergyEquation
+	}
+
+	switch {
+	case u.Activity != "":
+		preset, ok := FindActivityPreset(u.Activity)
+		if !ok {
+			return ErrInvalidActivity
+		}
+		u.ActivityLevel = preset.Multiplier
+	case u.ActivityLevel != 0:
+		if preset, ok := legacyActivityPreset(u.ActivityLevel); ok {
+			u.Activity, u.ActivityLevel = preset.Key, preset.Multiplier
+			break
+		}
+		preset := nearestActivityPreset(u.ActivityLevel)
+		if math.Abs(preset.Multiplier-u.ActivityLevel) > 0.001 {
+			return ErrInvalidActivity
+		}
+		u.Activity, u.ActivityLevel = preset.Key, preset.Multiplier
+	}
+	return nil
+}
+
+// BackfillEnergyProfile preenche os campos criados para as equações nos
+// perfis antigos: o sexo vem do gênero quando ele indica o sexo (M, F, male,
+// female...), e o nível de atividade vem da escala de 1 a 5 do app ou do
+// preset mais próximo do fator gravado. Fatores fora da faixa dos presets
+// ficam sem nível, para o usuário escolher.
+func BackfillEnergyProfile(db *gorm.DB) error {
+	for gender, sex := range legacyGenderSex {
+		if err := db.Model(&User{}).
+			Where("(sex IS NULL OR sex = '') AND LOWER(TRIM(gender)) = ?", gender).
+			UpdateColumn("sex", sex).Error; err != nil {
+			return err
+		}
+	}
+
+	unset := "(activity IS NULL OR activity = '')"
+	for i, preset := range ActivityPresets {
+		if err := db.Model(&User{}).Where(unset+" AND activity_level = ?", i+1).UpdateColumns(map[string]interface{}{
+			"activity":       preset.Key,
+			"activity_level": preset.Multiplier,
+		}).Error; err != nil {
+			return err
+		}
+	}
+
+	for i, preset := range ActivityPresets {
+		// Cada faixa vai até a metade da distância para o preset vizinho; o
+		// primeiro e o último usam a mesma meia distância do lado de dentro
+		lower, upper := preset.Multiplier, preset.Multiplier
+		if i > 0 {
+			lower -= (preset.Multiplier - ActivityPresets[i-1].Multiplier) / 2
+		} else {
+			lower -= (ActivityPresets[i+1].Multiplier - preset.Multiplier) / 2
+		}
+		if i < len(ActivityPresets)-1 {
+			upper += (ActivityPresets[i+1].Multiplier - preset.Multiplier) / 2
+		} else {
+			upper += (preset.Multiplier - ActivityPresets[i-1].Multiplier) / 2
+		}
+		query := db.Model(&User{}).Where(unset+" AND activity_level >= ? AND activity_level < ?", lower, upper)
+		if err := query.UpdateColumns(map[string]interface{}{
+			"activity":       preset.Key,
+			"activity_level": preset.Multiplier,
+		}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// energyInputs reúne e confere os dados exigidos pela equação
+func (u User) energyInputs(equation EnergyEquation) (EnergyInputs, error) {
+	in := EnergyInputs{
+		Sex:     u.Sex,
+		Age:     u.Age,
+		Weight:  u.Weight,
+		Height:  u.Height,
+		BodyFat: u.BodyFat,
+	}
+	if u.Weight == 0 || (equation.RequiresHeight && u.Height == 0) ||
+		(equation.RequiresSex && (u.Sex == "" || u.Age == 0)) {
+		return in, ErrIncompleteProfile
+	}
+	if equation.RequiresBodyFat {
+		if u.BodyFat == nil {
+			return in, ErrBodyFatRequired
+		}
+		in.LeanMass = math.Round(u.Weight*(1-*u.BodyFat/100)*100) / 100
+	}
+	if !equation.RequiresBodyFat {
+		in.BodyFat = nil
+	}
+	if !equation.RequiresSex {
+		in.Sex, in.Age = "", 0
+	}
+	if !equation.RequiresHeight {
+		in.Height = 0
+	}
+
+	preset, ok := FindActivityPreset(u.Activity)
+	if !ok {
+		return in, ErrIncompleteProfile
+	}
+	in.Activity, in.ActivityMultiplier = preset.Key, preset.Multiplier
+	return in, nil
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeEnergyProfile(t *testing.T) {
+	tests := []struct {
+		name         string
+		user         User
+		wantSex      string
+		wantActivity string
+		wantLevel    float64
+		wantErr      error
+	}{
+		{"escala do app 1", User{ActivityLevel: 1}, "", "sedentary", 1.2, nil},
+		{"escala do app 2", User{ActivityLevel: 2}, "", "light", 1.375, nil},
+		{"escala do app 3", User{ActivityLevel: 3}, "", "moderate", 1.55, nil},
+		{"escala do app 4", User{ActivityLevel: 4}, "", "active", 1.725, nil},
+		{"escala do app 5", User{ActivityLevel: 5}, "", "very_active", 1.9, nil},
+		{"fator do preset", User{ActivityLevel: 1.55}, "", "moderate", 1.55, nil},
+		{"chave do preset", User{Activity: "light", ActivityLevel: 3}, "", "light", 1.375, nil},
+		{"fator desconhecido", User{ActivityLevel: 1.4}, "", "", 0, ErrInvalidActivity},
+		{"fora da escala", User{ActivityLevel: 6}, "", "", 0, ErrInvalidActivity},
+		{"chave desconhecida", User{Activity: "couch"}, "", "", 0, ErrInvalidActivity},
+		{"gênero M", User{Gender: "M"}, SexMale, "", 0, nil},
+		{"gênero f minúsculo", User{Gender: " f "}, SexFemale, "", 0, nil},
+		{"gênero Outro", User{Gender: "Outro"}, "", "", 0, nil},
+		{"sexo informado prevalece", User{Gender: "M", Sex: SexFemale}, SexFemale, "", 0, nil},
+		{"sexo inválido", User{Sex: "M"}, "", "", 0, ErrInvalidSex},
+		{"equação inválida", User{EnergyEquation: "x"}, "", "", 0, ErrInvalidEnergyEquation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := tt.user
+			err := user.NormalizeEnergyProfile()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("erro = %v, esperado %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if user.Sex != tt.wantSex || user.Activity != tt.wantActivity || user.ActivityLevel != tt.wantLevel {
+				t.Errorf("sex = %q, activity = %q, activity_level = %v", user.Sex, user.Activity, user.ActivityLevel)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package models
+
+import (
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// FastingSession é um período de jejum iniciado pelo usuário. Termina quando o
+// usuário encerra ou quando registra uma refeição depois do início.
+type FastingSession struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	UserID         uint       `gorm:"index" json:"user_id"`
+	StartedAt      time.Time  `json:"started_at"`
+	EndedAt        *time.Time `json:"ended_at"`
+	TargetHours    float64    `json:"target_hours"`
+	BrokenByMealID *uint      `json:"broken_by_meal_id"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+func MigrateFasting(db *gorm.DB) error {
+	return db.AutoMigrate(&FastingSession{})
+}
+
+// Duration retorna a duração do jejum; sessões em andamento contam até agora
+func (s FastingSession) Duration() time.Duration {
+	end := time.Now()
+	if s.EndedAt != nil {
+		end = *s.EndedAt
+	}
+	return end.Sub(s.StartedAt)
+}
+
+// Completed indica se o jejum encerrado atingiu a meta
+func (s FastingSession) Completed() bool {
+	return s.EndedAt != nil && s.Duration().Hours() >= s.TargetHours
+}
+
+// ActiveFast retorna o jejum em andamento do usuário
+func ActiveFast(db *gorm.DB, userID uint) (*FastingSession, error) {
+	var session FastingSession
+	result := db.Where("user_id = ? AND ended_at IS NULL", userID).Order("started_at DESC").Limit(1).Find(&session)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return nil, result.Error
+	}
+	return &session, nil
+}
+
+// BreakActiveFast encerra o jejum em andamento quando a refeição acontece
+// depois do início dele. Retorna a sessão encerrada, se houver.
+func BreakActiveFast(db *gorm.DB, meal Meal) (*FastingSession, error) {
+	session, err := ActiveFast(db, meal.UserID)
+	if err != nil || session == nil || !meal.CreatedAt.After(session.StartedAt) {
+		return nil, err
+	}
+
+	endedAt := meal.CreatedAt
+	session.EndedAt = &endedAt
+	session.BrokenByMealID = &meal.ID
+	if err := db.Save(session).Error; err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// FastingStats resume o histórico de jejum e as janelas entre refeições
+type FastingStats struct {
+	Sessions            int     `json:"sessions"`
+	CompletedSessions   int     `json:"completed_sessions"`
+	CurrentStreak       int     `json:"current_streak"` // Dias seguidos com jejum concluído
+	LongestStreak       int     `json:"longest_streak"`
+	AverageFastHours    float64 `json:"average_fast_hours"`   // Média das sessões encerradas
+	AverageWindowHours  float64 `json:"average_window_hours"` // Última refeição de um dia até a primeira do seguinte
+	AverageEatingHours  float64 `json:"average_eating_hours"` // Primeira até a última refeição do dia
+	DaysWithMealWindows int     `json:"days_with_meal_windows"`
+}
+
+// BuildFastingStats calcula sequências e médias no fuso do usuário. As sessões
+// e as refeições devem cobrir o período analisado.
+func BuildFastingStats(sessions []FastingSession, mealTimes []time.Time, loc *time.Location, today time.Time) FastingStats {
+	stats := FastingStats{Sessions: len(sessions)}
+
+	// Sequência: dias (no fuso do usuário) em que um jejum concluído terminou
+	completedDays := make(map[string]bool)
+	var totalFast float64
+	var endedSessions int
+	for _, session := range sessions {
+		if session.EndedAt == nil {
+			continue
+		}
+		endedSessions++
+		totalFast += session.Duration().Hours()
+		if session.Completed() {
+			stats.CompletedSessions++
+			completedDays[session.EndedAt.In(loc).Format(DateLayout)] = true
+		}
+	}
+	if endedSessions > 0 {
+		stats.AverageFastHours = totalFast / float64(endedSessions)
+	}
+
+	// A sequência atual pode começar ontem, se hoje ainda não houve jejum concluído
+	day := today.In(loc)
+	if !completedDays[day.Format(DateLayout)] {
+		day = day.AddDate(0, 0, -1)
+	}
+	for completedDays[day.Format(DateLayout)] {
+		stats.CurrentStreak++
+		day = day.AddDate(0, 0, -1)
+	}
+
+	days := make([]string, 0, len(completedDays))
+	for date := range completedDays {
+		days = append(days, date)
+	}
+	sort.Strings(days)
+	streak := 0
+	for i, date := range days {
+		if i > 0 && consecutiveDates(days[i-1], date, loc) {
+			streak++
+		} else {
+			streak = 1
+		}
+		stats.LongestStreak = max(stats.LongestStreak, streak)
+	}
+
+	// Janelas a partir dos horários das refeições
+	type dayMeals struct{ first, last time.Time }
+	byDay := make(map[string]*dayMeals)
+	for _, mealTime := range mealTimes {
+		local := mealTime.In(loc)
+		key := local.Format(DateLayout)
+		entry, ok := byDay[key]
+		if !ok {
+			byDay[key] = &dayMeals{first: local, last: local}
+			continue
+		}
+		if local.Before(entry.first) {
+			entry.first = local
+		}
+		if local.After(entry.last) {
+			entry.last = local
+		}
+	}
+
+	var totalWindow, totalEating float64
+	var windows int
+	for key, entry := range byDay {
+		totalEating += entry.last.Sub(entry.first).Hours()
+
+		date, _ := time.ParseInLocation(DateLayout, key, loc)
+		previous, ok := byDay[date.AddDate(0, 0, -1).Format(DateLayout)]
+		if ok {
+			totalWindow += entry.first.Sub(previous.last).Hours()
+			windows++
+		}
+	}
+	if len(byDay) > 0 {
+		stats.AverageEatingHours = totalEating / float64(len(byDay))
+	}
+	if windows > 0 {
+		stats.AverageWindowHours = totalWindow / float64(windows)
+	}
+	stats.DaysWithMealWindows = windows
+
+	return stats
+}
+
+func consecutiveDates(previous, current string, loc *time.Location) bool {
+	prev, err := time.ParseInLocation(DateLayout, previous, loc)
+	if err != nil {
+		return false
+	}
+	return prev.AddDate(0, 0, 1).Format(DateLayout) == current
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// O catálogo só muda pela importação da TACO (cmd/taco-import), feita em
+// outro processo; a cópia em memória é renovada depois deste intervalo
+const foodCatalogTTL = 10 * time.Minute
+
+var foodCatalog struct {
+	sync.Mutex
+	foods    []Food
+	loadedAt time.Time
+}
+
+// CatalogFoods retorna todos os alimentos do catálogo a partir de uma cópia em
+// memória. A fatia é compartilhada entre as requisições e não deve ser alterada.
+func CatalogFoods(db *gorm.DB) ([]Food, error) {
+	foodCatalog.Lock()
+	defer foodCatalog.Unlock()
+
+	if foodCatalog.foods != nil && time.Since(foodCatalog.loadedAt) < foodCatalogTTL {
+		return foodCatalog.foods, nil
+	}
+	var foods []Food
+	if err := db.Order("id").Find(&foods).Error; err != nil {
+		return nil, err
+	}
+	foodCatalog.foods, foodCatalog.loadedAt = foods, time.Now()
+	return foods, nil
+}
+
+// HasNutrients indica se o alimento tem a composição centesimal analisada.
+// Na TACO, valores não analisados (*) são lidos como zero, e alguns alimentos
+// não têm umidade, energia nem macronutrientes; o sal, com umidade, é válido.
+func (f Food) HasNutrients() bool {
+	return f.Calories > 0 || f.Protein > 0 || f.Carbs > 0 || f.Fat > 0 || f.Water > 0
+}
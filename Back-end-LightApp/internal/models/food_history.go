@@ -0,0 +1,243 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Janelas de histórico usadas para alimentos recentes e frequentes
+const (
+	RecentFoodsWindow   = 30 * 24 * time.Hour
+	FrequentFoodsWindow = 90 * 24 * time.Hour
+)
+
+// Faixas de horário (hora inicial inclusiva, hora final exclusiva)
+var timeOfDayRanges = map[string][2]int{
+	"morning":   {5, 11},
+	"afternoon": {11, 17},
+	"evening":   {17, 22},
+	"night":     {22, 5},
+}
+
+// FoodHistoryFilter restringe o histórico por tipo de refeição e horário
+type FoodHistoryFilter struct {
+	MealType  string
+	TimeOfDay string
+	Limit     int
+}
+
+// FoodUsage resume como o usuário costuma registrar um alimento
+type FoodUsage struct {
+	Food     Food      `json:"food"`
+	Count    int       `json:"count"`
+	LastUsed time.Time `json:"last_used"`
+	Amount   float64   `json:"amount"`   // Gramas mais usadas para o alimento
+	Quantity float64   `json:"quantity"` // Quantidade mais usada na unidade abaixo
+	Unit     string    `json:"unit"`
+}
+
+type foodLogRow struct {
+	FoodID    uint
+	Amount    float64
+	Quantity  float64
+	Unit      string
+	CreatedAt time.Time
+}
+
+type portionKey struct {
+	amount   float64
+	quantity float64
+	unit     string
+}
+
+// IsValidTimeOfDay indica se a faixa de horário é conhecida (vazio é aceito)
+func IsValidTimeOfDay(timeOfDay string) bool {
+	if timeOfDay == "" {
+		return true
+	}
+	_, ok := timeOfDayRanges[timeOfDay]
+	return ok
+}
+
+// RecentFoods retorna os alimentos usados mais recentemente pelo usuário
+func RecentFoods(db *gorm.DB, userID uint, filter FoodHistoryFilter) ([]FoodUsage, error) {
+	usages, err := foodUsages(db, userID, filter, time.Now().Add(-RecentFoodsWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(usages, func(i, j int) bool {
+		return usages[i].LastUsed.After(usages[j].LastUsed)
+	})
+	return limitUsages(usages, filter.Limit), nil
+}
+
+// FrequentFoods retorna os alimentos mais registrados pelo usuário
+func FrequentFoods(db *gorm.DB, userID uint, filter FoodHistoryFilter) ([]FoodUsage, error) {
+	usages, err := foodUsages(db, userID, filter, time.Now().Add(-FrequentFoodsWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(usages, func(i, j int) bool {
+		if usages[i].Count != usages[j].Count {
+			return usages[i].Count > usages[j].Count
+		}
+		return usages[i].LastUsed.After(usages[j].LastUsed)
+	})
+	return limitUsages(usages, filter.Limit), nil
+}
+
+func foodUsages(db *gorm.DB, userID uint, filter FoodHistoryFilter, since time.Time) ([]FoodUsage, error) {
+	query := db.Table("meal_items").
+		Select("meal_items.food_id, meal_items.amount, meal_items.quantity, meal_items.unit, meals.created_at").
+		Joins("JOIN meals ON meals.id = meal_items.meal_id").
+		Where("meals.user_id = ? AND meals.created_at >= ?", userID, since).
+		Where("meal_items.food_id IS NOT NULL")
+
+	if filter.MealType != "" {
+		query = query.Where("meals.meal_type = ?", filter.MealType)
+	}
+
+	// A faixa de horário é aplicada aqui, na hora local do usuário; o banco
+	// leria a hora no fuso da sessão
+	var loc *time.Location
+	if filter.TimeOfDay != "" {
+		if !IsValidTimeOfDay(filter.TimeOfDay) {
+			return nil, fmt.Errorf("faixa de horário inválida: %s", filter.TimeOfDay)
+		}
+		var err error
+		if loc, err = UserLocation(db, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	var rows []foodLogRow
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	if loc != nil {
+		rows = slices.DeleteFunc(rows, func(row foodLogRow) bool {
+			return !inTimeOfDay(filter.TimeOfDay, row.CreatedAt.In(loc))
+		})
+	}
+
+	byFood := make(map[uint]*FoodUsage)
+	portions := make(map[uint]map[portionKey]int)
+	var order []uint
+
+	for _, row := range rows {
+		usage, ok := byFood[row.FoodID]
+		if !ok {
+			usage = &FoodUsage{}
+			byFood[row.FoodID] = usage
+			portions[row.FoodID] = make(map[portionKey]int)
+			order = append(order, row.FoodID)
+		}
+
+		usage.Count++
+		if row.CreatedAt.After(usage.LastUsed) {
+			usage.LastUsed = row.CreatedAt
+		}
+
+		key := portionKey{amount: row.Amount, quantity: row.Quantity, unit: row.Unit}
+		if key.unit == "" {
+			key.unit = "g"
+		}
+		if key.quantity == 0 {
+			key.quantity = row.Amount
+		}
+		portions[row.FoodID][key]++
+	}
+
+	if len(order) == 0 {
+		return []FoodUsage{}, nil
+	}
+
+	var foods []Food
+	if err := db.Where("id IN ?", order).Find(&foods).Error; err != nil {
+		return nil, err
+	}
+	foodsByID := make(map[uint]Food, len(foods))
+	for _, food := range foods {
+		foodsByID[food.ID] = food
+	}
+
+	usages := make([]FoodUsage, 0, len(order))
+	for _, foodID := range order {
+		food, ok := foodsByID[foodID]
+		if !ok {
+			continue
+		}
+
+		usage := byFood[foodID]
+		usage.Food = food
+
+		// Porção mais registrada; em caso de empate, a maior quantidade
+		var best portionKey
+		bestCount := 0
+		for key, count := range portions[foodID] {
+			switch {
+			case count > bestCount,
+				count == bestCount && key.amount > best.amount,
+				count == bestCount && key.amount == best.amount && key.unit < best.unit:
+				best, bestCount = key, count
+			}
+		}
+		usage.Amount = best.amount
+		usage.Quantity = best.quantity
+		usage.Unit = best.unit
+
+		usages = append(usages, *usage)
+	}
+
+	return usages, nil
+}
+
+// inTimeOfDay indica se o horário local cai na faixa
+func inTimeOfDay(timeOfDay string, at time.Time) bool {
+	hours := timeOfDayRanges[timeOfDay]
+	hour := at.Hour()
+	if hours[0] < hours[1] {
+		return hour >= hours[0] && hour < hours[1]
+	}
+	// Faixa que atravessa a meia-noite
+	return hour >= hours[0] || hour < hours[1]
+}
+
+func limitUsages(usages []FoodUsage, limit int) []FoodUsage {
+	if limit > 0 && len(usages) > limit {
+		return usages[:limit]
+	}
+	return usages
+}
+
+// FoodEnergy é a participação de um alimento (ou registro rápido) nas calorias
+type FoodEnergy struct {
+	FoodID   *uint   `json:"food_id"`
+	Name     string  `json:"name"`
+	Count    int64   `json:"count"`
+	Grams    float64 `json:"grams"` // Zero em registros rápidos
+	Calories float64 `json:"calories"`
+}
+
+// TopFoodsByEnergy lista os alimentos que mais contribuíram com calorias no
+// intervalo [from, to). Registros rápidos são agrupados pela descrição.
+func TopFoodsByEnergy(db *gorm.DB, userID uint, from, to time.Time, limit int) ([]FoodEnergy, error) {
+	var foods []FoodEnergy
+	err := db.Table("meal_items").
+		Select("meal_items.food_id, COALESCE(foods.name, meal_items.label) AS name, COUNT(*) AS count, "+
+			"COALESCE(SUM(meal_items.amount), 0) AS grams, COALESCE(SUM(meal_items.calories), 0) AS calories").
+		Joins("JOIN meals ON meals.id = meal_items.meal_id").
+		Joins("LEFT JOIN foods ON foods.id = meal_items.food_id").
+		Where("meals.user_id = ? AND meals.created_at >= ? AND meals.created_at < ?", userID, from, to).
+		Group("meal_items.food_id, COALESCE(foods.name, meal_items.label)").
+		Order("calories DESC").
+		Limit(limit).
+		Scan(&foods).Error
+	return foods, err
+}
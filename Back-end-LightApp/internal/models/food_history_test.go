@@ -0,0 +1,35 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInTimeOfDay(t *testing.T) {
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Skip("base de fusos indisponível")
+	}
+	// 09:30 em São Paulo é 12:30 em UTC: café da manhã, não tarde
+	at := time.Date(2026, 3, 10, 12, 30, 0, 0, time.UTC).In(saoPaulo)
+
+	tests := []struct {
+		timeOfDay string
+		at        time.Time
+		want      bool
+	}{
+		{"morning", at, true},
+		{"afternoon", at, false},
+		{"morning", time.Date(2026, 3, 10, 11, 0, 0, 0, saoPaulo), false},
+		{"afternoon", time.Date(2026, 3, 10, 11, 0, 0, 0, saoPaulo), true},
+		{"evening", time.Date(2026, 3, 10, 21, 59, 0, 0, saoPaulo), true},
+		{"night", time.Date(2026, 3, 10, 23, 0, 0, 0, saoPaulo), true},
+		{"night", time.Date(2026, 3, 10, 4, 59, 0, 0, saoPaulo), true},
+		{"night", time.Date(2026, 3, 10, 5, 0, 0, 0, saoPaulo), false},
+	}
+	for _, tt := range tests {
+		if got := inTimeOfDay(tt.timeOfDay, tt.at); got != tt.want {
+			t.Errorf("inTimeOfDay(%q, %s) = %v, esperado %v", tt.timeOfDay, tt.at.Format("15:04"), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Meta de hidratação derivada do peso (ml por kg) e padrão quando não há peso
+const (
+	WaterMLPerKg       = 35.0
+	DefaultWaterGoalML = 2000.0
+)
+
+// HydrationEntry registra uma bebida consumida pelo usuário
+type HydrationEntry struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	UserID     uint      `gorm:"index" json:"user_id"`
+	AmountML   float64   `json:"amount_ml"`
+	Beverage   string    `gorm:"default:water" json:"beverage"` // water, coffee, tea, juice, milk, other
+	ConsumedAt time.Time `gorm:"index" json:"consumed_at"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func MigrateHydration(db *gorm.DB) error {
+	return db.AutoMigrate(&HydrationEntry{})
+}
+
+// IsValidBeverage indica se o tipo de bebida é conhecido (vazio é aceito)
+func IsValidBeverage(beverage string) bool {
+	switch beverage {
+	case "", "water", "coffee", "tea", "juice", "milk", "other":
+		return true
+	}
+	return false
+}
+
+// WaterGoal retorna a meta diária de água em ml: a definida pelo usuário ou a derivada do peso
+func (u User) WaterGoal() (goal float64, source string) {
+	switch {
+	case u.WaterGoalML > 0:
+		return u.WaterGoalML, "user"
+	case u.Weight > 0:
+		return u.Weight * WaterMLPerKg, "weight"
+	default:
+		return DefaultWaterGoalML, "default"
+	}
+}
+
+// HydrationSummary consolida a água ingerida em um dia
+type HydrationSummary struct {
+	Date          string  `json:"date"`
+	DrankML       float64 `json:"drank_ml"`
+	FromFoodML    float64 `json:"from_food_ml"`
+	TotalML       float64 `json:"total_ml"`
+	GoalML        float64 `json:"goal_ml"`
+	GoalSource    string  `json:"goal_source"`
+	RemainingML   float64 `json:"remaining_ml"`
+	GoalPercent   float64 `json:"goal_percent"`
+	EntriesLogged int     `json:"entries_logged"`
+}
+
+// WaterFromFood soma a água contida nos alimentos registrados no intervalo
+func WaterFromFood(db *gorm.DB, userID uint, start, end time.Time) (float64, error) {
+	total, err := AggregateNutrients(db, NutrientFilter{UserID: userID, From: start, To: end})
+	return total.Water, err
+}
+
+// BuildHydrationSummary calcula o resumo de hidratação de um dia
+func BuildHydrationSummary(db *gorm.DB, user User, day time.Time) (HydrationSummary, []HydrationEntry, error) {
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	end := start.AddDate(0, 0, 1)
+
+	var entries []HydrationEntry
+	if err := db.Where("user_id = ? AND consumed_at >= ? AND consumed_at < ?", user.ID, start, end).
+		Order("consumed_at").Find(&entries).Error; err != nil {
+		return HydrationSummary{}, nil, err
+	}
+
+	fromFood, err := WaterFromFood(db, user.ID, start, end)
+	if err != nil {
+		return HydrationSummary{}, nil, err
+	}
+
+	summary := HydrationSummary{
+		Date:          start.Format("2006-01-02"),
+		FromFoodML:    fromFood,
+		EntriesLogged: len(entries),
+	}
+	for _, entry := range entries {
+		summary.DrankML += entry.AmountML
+	}
+	summary.TotalML = summary.DrankML + summary.FromFoodML
+	summary.GoalML, summary.GoalSource = user.WaterGoal()
+	summary.RemainingML = max(summary.GoalML-summary.TotalML, 0)
+	if summary.GoalML > 0 {
+		summary.GoalPercent = summary.TotalML / summary.GoalML * 100
+	}
+
+	return summary, entries, nil
+}
@@ -0,0 +1,182 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Modos de definição de uma meta de macronutriente
+const (
+	MacroModePercent   = "percent"   // Percentual das calorias
+	MacroModePerKg     = "per_kg"    // Gramas por kg de peso corporal
+	MacroModeGrams     = "grams"     // Gramas fixas
+	MacroModeRemainder = "remainder" // Completa as calorias restantes
+)
+
+// Tolerância entre as calorias dos macros e a meta calórica, em percentual
+const macroCalorieTolerance = 5.0
+
+// MacroTarget define a meta de um macronutriente
+type MacroTarget struct {
+	Mode  string  `json:"mode"`
+	Value float64 `json:"value"` // Ignorado no modo remainder
+}
+
+// MacroTargets é a configuração de metas do usuário, montada a partir de um
+// preset ou definida pelo nutricionista ("2 g/kg de proteína, 25% de gordura,
+// carboidratos com o restante")
+type MacroTargets struct {
+	Preset   string      `json:"preset"`   // Vazio quando personalizado
+	Calories float64     `json:"calories"` // Zero: meta calculada pelo objetivo
+	Protein  MacroTarget `json:"protein"`
+	Carbs    MacroTarget `json:"carbs"`
+	Fat      MacroTarget `json:"fat"`
+	Fiber    float64     `json:"fiber"` // Gramas; zero: proporcional às calorias
+}
+
+// MacroPresets são os perfis prontos de distribuição de macronutrientes
+var MacroPresets = map[string]MacroTargets{
+	"balanced": {
+		Protein: MacroTarget{Mode: MacroModePercent, Value: 20},
+		Fat:     MacroTarget{Mode: MacroModePercent, Value: 30},
+		Carbs:   MacroTarget{Mode: MacroModeRemainder},
+	},
+	"low_carb": {
+		Protein: MacroTarget{Mode: MacroModePercent, Value: 25},
+		Carbs:   MacroTarget{Mode: MacroModePercent, Value: 25},
+		Fat:     MacroTarget{Mode: MacroModeRemainder},
+	},
+	"keto": {
+		Protein: MacroTarget{Mode: MacroModePercent, Value: 20},
+		Carbs:   MacroTarget{Mode: MacroModePercent, Value: 5},
+		Fat:     MacroTarget{Mode: MacroModeRemainder},
+	},
+	"high_protein": {
+		Protein: MacroTarget{Mode: MacroModePerKg, Value: 2},
+		Fat:     MacroTarget{Mode: MacroModePercent, Value: 25},
+		Carbs:   MacroTarget{Mode: MacroModeRemainder},
+	},
+	"dash": {
+		Protein: MacroTarget{Mode: MacroModePercent, Value: 18},
+		Carbs:   MacroTarget{Mode: MacroModePercent, Value: 55},
+		Fat:     MacroTarget{Mode: MacroModeRemainder},
+	},
+}
+
+// ApplyPreset substitui a distribuição pelos macros do preset, mantendo as
+// calorias e a fibra escolhidas
+func (t *MacroTargets) ApplyPreset(name string) error {
+	preset, ok := MacroPresets[name]
+	if !ok {
+		return errors.New("Preset de macronutrientes desconhecido: " + name)
+	}
+	t.Preset = name
+	t.Protein, t.Carbs, t.Fat = preset.Protein, preset.Carbs, preset.Fat
+	return nil
+}
+
+// Validate confere a configuração sem depender do perfil: modos conhecidos,
+// valores positivos, no máximo um macro com o restante e percentuais que não
+// passam de 100%
+func (t MacroTargets) Validate() error {
+	if t.Calories < 0 || t.Fiber < 0 {
+		return errors.New("Calorias e fibra não podem ser negativas")
+	}
+
+	remainders := 0
+	percent := 0.0
+	for _, macro := range t.macros() {
+		switch macro.target.Mode {
+		case MacroModeRemainder:
+			remainders++
+			continue
+		case MacroModePercent:
+			percent += macro.target.Value
+		case MacroModePerKg, MacroModeGrams:
+		default:
+			return fmt.Errorf("Modo inválido para %s (use percent, per_kg, grams ou remainder)", macro.name)
+		}
+		if macro.target.Value <= 0 {
+			return fmt.Errorf("Informe um valor positivo para %s", macro.name)
+		}
+	}
+	if remainders > 1 {
+		return errors.New("Apenas um macronutriente pode completar as calorias restantes")
+	}
+	if percent > 100 {
+		return errors.New("A soma dos percentuais passa de 100%")
+	}
+	return nil
+}
+
+// Resolve converte a configuração em gramas para as calorias e o peso
+// informados. Os macros precisam fechar a meta calórica: o macro com o
+// restante não pode ficar negativo e, sem ele, a soma precisa ficar dentro
+// da tolerância.
+func (t MacroTargets) Resolve(calories, weight float64) (NutritionTargets, error) {
+	if err := t.Validate(); err != nil {
+		return NutritionTargets{}, err
+	}
+
+	grams := make(map[string]float64, 3)
+	used := 0.0
+	remainder := ""
+	for _, macro := range t.macros() {
+		switch macro.target.Mode {
+		case MacroModePercent:
+			grams[macro.name] = calories * macro.target.Value / 100 / macro.kcalPerGram
+		case MacroModePerKg:
+			if weight <= 0 {
+				return NutritionTargets{}, ErrIncompleteProfile
+			}
+			grams[macro.name] = macro.target.Value * weight
+		case MacroModeGrams:
+			grams[macro.name] = macro.target.Value
+		case MacroModeRemainder:
+			remainder = macro.name
+			continue
+		}
+		used += grams[macro.name] * macro.kcalPerGram
+	}
+
+	if remainder != "" {
+		left := calories - used
+		if left < 0 {
+			return NutritionTargets{}, fmt.Errorf("Os outros macros já somam %.0f kcal, acima da meta de %.0f kcal", used, calories)
+		}
+		for _, macro := range t.macros() {
+			if macro.name == remainder {
+				grams[remainder] = left / macro.kcalPerGram
+			}
+		}
+	} else if calories > 0 && math.Abs(used-calories)/calories*100 > macroCalorieTolerance {
+		return NutritionTargets{}, fmt.Errorf("Os macros somam %.0f kcal, mas a meta é de %.0f kcal", used, calories)
+	}
+
+	fiber := t.Fiber
+	if fiber == 0 {
+		fiber = calories / 1000 * fiberPer1000kcal
+	}
+	return NutritionTargets{
+		Calories: calories,
+		Protein:  grams["proteína"],
+		Carbs:    grams["carboidratos"],
+		Fat:      grams["gordura"],
+		Fiber:    fiber,
+	}, nil
+}
+
+type namedMacro struct {
+	name        string
+	target      MacroTarget
+	kcalPerGram float64
+}
+
+func (t MacroTargets) macros() []namedMacro {
+	return []namedMacro{
+		{"proteína", t.Protein, KcalPerGramProtein},
+		{"carboidratos", t.Carbs, KcalPerGramCarbs},
+		{"gordura", t.Fat, KcalPerGramFat},
+	}
+}
@@ -1,19 +1,126 @@
 package models
 
 import (
+	"errors"
+	"slices"
+	"strings"
 	"time"
+
+	"gorm.io/gorm"
+)
+
+// DateLayout é o formato de data usado na API (AAAA-MM-DD)
+const DateLayout = "2006-01-02"
+
+// Tipos de refeição aceitos
+const (
+	MealTypeBreakfast = "breakfast"
+	MealTypeLunch     = "lunch"
+	MealTypeDinner    = "dinner"
+	MealTypeSnack     = "snack"
 )
 
 type Meal struct {
-	ID        uint       `gorm:"primaryKey" json:"id"`
-	UserID    uint       `json:"user_id"`
-	CreatedAt time.Time  `json:"created_at"`
-	Items     []MealItem `gorm:"foreignKey:MealID" json:"items"`
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	UserID        uint       `json:"user_id"`
+	MealType      string     `gorm:"index" json:"meal_type"`
+	PlannedMealID *uint      `gorm:"index" json:"planned_meal_id"` // Refeição do plano marcada como consumida
+	CreatedAt     time.Time  `json:"created_at"`
+	Items         []MealItem `gorm:"foreignKey:MealID" json:"items"`
+	Photo         *MealPhoto `gorm:"foreignKey:MealID" json:"photo,omitempty"`
+
+	// Dados comportamentais para acompanhamento nutricional
+	Note        string   `json:"note"`
+	Hunger      *int     `json:"hunger"`   // Fome antes da refeição, de 1 a 5
+	Fullness    *int     `json:"fullness"` // Saciedade depois da refeição, de 1 a 5
+	ContextTags []string `gorm:"serializer:json;type:text" json:"context_tags"`
+	Mood        string   `gorm:"index" json:"mood"`
+
+	BrokenFast *FastingSession `gorm:"-" json:"broken_fast,omitempty"` // Jejum encerrado por esta refeição
 }
 
 type MealItem struct {
-	ID     uint    `gorm:"primaryKey" json:"id"`
-	MealID uint    `json:"meal_id"`
-	FoodID uint    `json:"food_id"`
-	Amount float64 `json:"amount"` // Quantidade em gramas
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	MealID     uint       `json:"meal_id"`
+	FoodID     *uint      `json:"food_id"` // Nulo em registros rápidos
+	QuickAdd   bool       `gorm:"default:false" json:"quick_add"`
+	Label      string     `json:"label"`    // Descrição livre do registro rápido
+	Amount     float64    `json:"amount"`   // Quantidade em gramas
+	Quantity   float64    `json:"quantity"` // Quantidade na unidade informada pelo usuário
+	Unit       string     `gorm:"default:g" json:"unit"`
+	Nutrients             // Valores da porção no momento do registro
+	SnapshotAt *time.Time `json:"snapshot_at"`
+}
+
+func MigrateMeal(db *gorm.DB) error {
+	return db.AutoMigrate(&Meal{}, &MealItem{})
+}
+
+// Marcadores de contexto e humor aceitos
+var (
+	MealContextTags = []string{"home", "work", "restaurant", "delivery", "school", "travel", "social"}
+	MealMoods       = []string{"happy", "calm", "neutral", "stressed", "anxious", "sad", "bored", "tired"}
+)
+
+// Tamanho máximo da anotação livre da refeição
+const MaxMealNoteLength = 1000
+
+// IsValidMealType indica se o tipo de refeição é conhecido (vazio é aceito)
+func IsValidMealType(mealType string) bool {
+	switch mealType {
+	case "", MealTypeBreakfast, MealTypeLunch, MealTypeDinner, MealTypeSnack:
+		return true
+	}
+	return false
+}
+
+// ValidateBehavior confere anotação, fome, saciedade, contexto e humor
+func (m *Meal) ValidateBehavior() error {
+	m.Note = strings.TrimSpace(m.Note)
+	if len(m.Note) > MaxMealNoteLength {
+		return errors.New("Anotação muito longa")
+	}
+	if m.Hunger != nil && (*m.Hunger < 1 || *m.Hunger > 5) {
+		return errors.New("Fome deve estar entre 1 e 5")
+	}
+	if m.Fullness != nil && (*m.Fullness < 1 || *m.Fullness > 5) {
+		return errors.New("Saciedade deve estar entre 1 e 5")
+	}
+	for _, tag := range m.ContextTags {
+		if !slices.Contains(MealContextTags, tag) {
+			return errors.New("Contexto inválido: " + tag)
+		}
+	}
+	if m.Mood != "" && !slices.Contains(MealMoods, m.Mood) {
+		return errors.New("Humor inválido")
+	}
+	return nil
+}
+
+// PrepareQuickAdd valida e normaliza um registro rápido, que carrega apenas
+// calorias e macros informados pelo usuário, sem alimento do catálogo
+func (item *MealItem) PrepareQuickAdd() error {
+	item.Label = strings.TrimSpace(item.Label)
+	if item.Label == "" {
+		return errors.New("Informe uma descrição para o registro rápido")
+	}
+	if item.Calories <= 0 {
+		return errors.New("Informe as calorias do registro rápido")
+	}
+	if item.Protein < 0 || item.Carbs < 0 || item.Fat < 0 {
+		return errors.New("Macronutrientes não podem ser negativos")
+	}
+
+	item.FoodID = nil
+	item.QuickAdd = true
+	if item.Unit == "" {
+		item.Unit = "porção"
+	}
+	if item.Quantity == 0 {
+		item.Quantity = 1
+	}
+
+	now := time.Now()
+	item.SnapshotAt = &now
+	return nil
 }
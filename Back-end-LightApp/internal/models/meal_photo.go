@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Variantes da foto servidas pela API
+const (
+	PhotoVariantOriginal  = "original"
+	PhotoVariantThumbnail = "thumbnail"
+)
+
+// MealPhoto é a foto de uma refeição, guardada no armazenamento configurado.
+// As chaves não são expostas: o acesso acontece por URLs assinadas.
+type MealPhoto struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	MealID       uint      `gorm:"uniqueIndex" json:"meal_id"`
+	UserID       uint      `gorm:"index" json:"user_id"`
+	Key          string    `json:"-"`
+	ThumbnailKey string    `json:"-"`
+	ContentType  string    `json:"content_type"`
+	Size         int64     `json:"size"` // Tamanho do original, em bytes
+	Width        int       `json:"width"`
+	Height       int       `json:"height"`
+	CreatedAt    time.Time `json:"created_at"`
+
+	URL          string `gorm:"-" json:"url,omitempty"`
+	ThumbnailURL string `gorm:"-" json:"thumbnail_url,omitempty"`
+}
+
+func MigrateMealPhoto(db *gorm.DB) error {
+	return db.AutoMigrate(&MealPhoto{})
+}
+
+// KeyFor retorna a chave da variante no armazenamento
+func (p MealPhoto) KeyFor(variant string) (string, bool) {
+	switch variant {
+	case PhotoVariantOriginal:
+		return p.Key, true
+	case PhotoVariantThumbnail:
+		return p.ThumbnailKey, true
+	}
+	return "", false
+}
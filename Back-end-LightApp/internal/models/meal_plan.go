@@ -0,0 +1,156 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MealPlan é um plano semanal: as refeições planejadas se repetem a cada
+// semana entre StartDate e EndDate (sem EndDate, o plano não expira)
+type MealPlan struct {
+	ID        uint          `gorm:"primaryKey" json:"id"`
+	UserID    uint          `gorm:"index" json:"user_id"`
+	CreatedBy uint          `json:"created_by"` // O próprio usuário ou o nutricionista com acesso
+	Name      string        `json:"name"`
+	StartDate time.Time     `json:"start_date"`
+	EndDate   *time.Time    `json:"end_date"`
+	Meals     []PlannedMeal `gorm:"foreignKey:PlanID;constraint:OnDelete:CASCADE" json:"meals"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// PlannedMeal é uma refeição do plano para um dia da semana
+type PlannedMeal struct {
+	ID       uint              `gorm:"primaryKey" json:"id"`
+	PlanID   uint              `gorm:"index" json:"plan_id"`
+	Weekday  int               `json:"weekday"` // 0 = domingo ... 6 = sábado
+	MealType string            `json:"meal_type"`
+	Time     string            `json:"time"` // Horário sugerido, HH:MM
+	Items    []PlannedMealItem `gorm:"foreignKey:PlannedMealID;constraint:OnDelete:CASCADE" json:"items"`
+}
+
+// PlannedMealItem segue o formato de MealItem: alimento do catálogo ou registro rápido
+type PlannedMealItem struct {
+	ID            uint    `gorm:"primaryKey" json:"id"`
+	PlannedMealID uint    `gorm:"index" json:"planned_meal_id"`
+	FoodID        *uint   `json:"food_id"`
+	QuickAdd      bool    `gorm:"default:false" json:"quick_add"`
+	Label         string  `json:"label"`
+	Amount        float64 `json:"amount"` // Quantidade em gramas
+	Quantity      float64 `json:"quantity"`
+	Unit          string  `json:"unit"`
+	Nutrients             // Valores planejados da porção
+}
+
+func MigrateMealPlan(db *gorm.DB) error {
+	return db.AutoMigrate(&MealPlan{}, &PlannedMeal{}, &PlannedMealItem{})
+}
+
+// CoversDate indica se o plano está vigente na data
+func (p MealPlan) CoversDate(day time.Time) bool {
+	// As datas do plano são meia-noite no fuso do usuário, o mesmo de day
+	date := day.Format(DateLayout)
+	if date < p.StartDate.In(day.Location()).Format(DateLayout) {
+		return false
+	}
+	return p.EndDate == nil || date <= p.EndDate.In(day.Location()).Format(DateLayout)
+}
+
+// MealsFor retorna as refeições planejadas para o dia da semana da data
+func (p MealPlan) MealsFor(day time.Time) []PlannedMeal {
+	var meals []PlannedMeal
+	if !p.CoversDate(day) {
+		return meals
+	}
+	for _, meal := range p.Meals {
+		if meal.Weekday == int(day.Weekday()) {
+			meals = append(meals, meal)
+		}
+	}
+	return meals
+}
+
+// Totals soma os nutrientes planejados da refeição
+func (m PlannedMeal) Totals() Nutrients {
+	var total Nutrients
+	for _, item := range m.Items {
+		total = total.Add(item.Nutrients)
+	}
+	return total
+}
+
+// Validate confere os dados da refeição planejada
+func (m PlannedMeal) Validate() error {
+	if m.Weekday < 0 || m.Weekday > 6 {
+		return errors.New("Dia da semana inválido (0 = domingo ... 6 = sábado)")
+	}
+	if m.MealType == "" || !IsValidMealType(m.MealType) {
+		return errors.New("Tipo de refeição inválido")
+	}
+	if m.Time != "" {
+		if _, err := time.Parse("15:04", m.Time); err != nil {
+			return errors.New("Horário inválido, use HH:MM")
+		}
+	}
+	return nil
+}
+
+// Prepare valida o item planejado e calcula seus nutrientes a partir do catálogo
+func (item *PlannedMealItem) Prepare(db *gorm.DB) error {
+	if item.FoodID == nil {
+		quick := MealItem{Label: item.Label, Quantity: item.Quantity, Unit: item.Unit, Nutrients: item.Nutrients}
+		if err := quick.PrepareQuickAdd(); err != nil {
+			return err
+		}
+		item.QuickAdd, item.Label, item.Quantity, item.Unit = true, quick.Label, quick.Quantity, quick.Unit
+		return nil
+	}
+
+	if item.Amount <= 0 {
+		return errors.New("Quantidade do alimento inválida")
+	}
+	var food Food
+	if err := db.First(&food, *item.FoodID).Error; err != nil {
+		return errors.New("Alimento não encontrado no TACO")
+	}
+	if item.Unit == "" {
+		item.Unit = "g"
+	}
+	if item.Quantity == 0 {
+		item.Quantity = item.Amount
+	}
+	item.QuickAdd = false
+	item.Label = ""
+	item.Nutrients = food.NutrientsFor(item.Amount)
+	return nil
+}
+
+// ToMealItem converte o item planejado em um item de refeição real. Itens do
+// catálogo recebem o snapshot dos valores atuais do alimento.
+func (item PlannedMealItem) ToMealItem(db *gorm.DB, mealID uint) (MealItem, error) {
+	mealItem := MealItem{
+		MealID:    mealID,
+		FoodID:    item.FoodID,
+		QuickAdd:  item.QuickAdd,
+		Label:     item.Label,
+		Amount:    item.Amount,
+		Quantity:  item.Quantity,
+		Unit:      item.Unit,
+		Nutrients: item.Nutrients,
+	}
+
+	if item.FoodID == nil {
+		now := time.Now()
+		mealItem.SnapshotAt = &now
+		return mealItem, nil
+	}
+
+	var food Food
+	if err := db.First(&food, *item.FoodID).Error; err != nil {
+		return MealItem{}, err
+	}
+	mealItem.SnapshotFrom(food)
+	return mealItem, nil
+}
@@ -0,0 +1,92 @@
+package models
+
+// Micronutrients são os minerais e vitaminas da TACO, nas mesmas bases de
+// Nutrients (por 100 g em Food, absolutos em MealItem)
+type Micronutrients struct {
+	Calcium    float64 `json:"calcium"`    // mg
+	Magnesium  float64 `json:"magnesium"`  // mg
+	Manganese  float64 `json:"manganese"`  // mg
+	Phosphorus float64 `json:"phosphorus"` // mg
+	Iron       float64 `json:"iron"`       // mg
+	Sodium     float64 `json:"sodium"`     // mg
+	Potassium  float64 `json:"potassium"`  // mg
+	Copper     float64 `json:"copper"`     // mg
+	Zinc       float64 `json:"zinc"`       // mg
+	VitaminA   float64 `json:"vitamin_a"`  // mcg RAE
+	Thiamin    float64 `json:"thiamin"`    // mg
+	Riboflavin float64 `json:"riboflavin"` // mg
+	VitaminB6  float64 `json:"vitamin_b6"` // mg
+	Niacin     float64 `json:"niacin"`     // mg
+	VitaminC   float64 `json:"vitamin_c"`  // mg
+}
+
+// micronutrientColumns lista as colunas de Micronutrients, na ordem de Values
+var micronutrientColumns = []string{
+	"calcium", "magnesium", "manganese", "phosphorus", "iron", "sodium", "potassium",
+	"copper", "zinc", "vitamin_a", "thiamin", "riboflavin", "vitamin_b6", "niacin", "vitamin_c",
+}
+
+func init() {
+	nutrientColumns = append(nutrientColumns, micronutrientColumns...)
+}
+
+// Values retorna os micronutrientes por chave (a mesma da coluna e do JSON)
+func (m Micronutrients) Values() map[string]float64 {
+	return map[string]float64{
+		"calcium":    m.Calcium,
+		"magnesium":  m.Magnesium,
+		"manganese":  m.Manganese,
+		"phosphorus": m.Phosphorus,
+		"iron":       m.Iron,
+		"sodium":     m.Sodium,
+		"potassium":  m.Potassium,
+		"copper":     m.Copper,
+		"zinc":       m.Zinc,
+		"vitamin_a":  m.VitaminA,
+		"thiamin":    m.Thiamin,
+		"riboflavin": m.Riboflavin,
+		"vitamin_b6": m.VitaminB6,
+		"niacin":     m.Niacin,
+		"vitamin_c":  m.VitaminC,
+	}
+}
+
+func (m Micronutrients) add(other Micronutrients) Micronutrients {
+	return Micronutrients{
+		Calcium:    m.Calcium + other.Calcium,
+		Magnesium:  m.Magnesium + other.Magnesium,
+		Manganese:  m.Manganese + other.Manganese,
+		Phosphorus: m.Phosphorus + other.Phosphorus,
+		Iron:       m.Iron + other.Iron,
+		Sodium:     m.Sodium + other.Sodium,
+		Potassium:  m.Potassium + other.Potassium,
+		Copper:     m.Copper + other.Copper,
+		Zinc:       m.Zinc + other.Zinc,
+		VitaminA:   m.VitaminA + other.VitaminA,
+		Thiamin:    m.Thiamin + other.Thiamin,
+		Riboflavin: m.Riboflavin + other.Riboflavin,
+		VitaminB6:  m.VitaminB6 + other.VitaminB6,
+		Niacin:     m.Niacin + other.Niacin,
+		VitaminC:   m.VitaminC + other.VitaminC,
+	}
+}
+
+func (m Micronutrients) scale(factor float64) Micronutrients {
+	return Micronutrients{
+		Calcium:    m.Calcium * factor,
+		Magnesium:  m.Magnesium * factor,
+		Manganese:  m.Manganese * factor,
+		Phosphorus: m.Phosphorus * factor,
+		Iron:       m.Iron * factor,
+		Sodium:     m.Sodium * factor,
+		Potassium:  m.Potassium * factor,
+		Copper:     m.Copper * factor,
+		Zinc:       m.Zinc * factor,
+		VitaminA:   m.VitaminA * factor,
+		Thiamin:    m.Thiamin * factor,
+		Riboflavin: m.Riboflavin * factor,
+		VitaminB6:  m.VitaminB6 * factor,
+		Niacin:     m.Niacin * factor,
+		VitaminC:   m.VitaminC * factor,
+	}
+}
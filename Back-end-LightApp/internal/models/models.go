@@ -5,12 +5,10 @@ import (
 )
 
 type Food struct {
-	ID       uint    `gorm:"primaryKey" json:"id"`
-	Name     string  `json:"name"`
-	Calories float64 `json:"calories"`
-	Protein  float64 `json:"protein"`
-	Carbs    float64 `json:"carbs"`
-	Fat      float64 `json:"fat"`
+	ID        uint   `gorm:"primaryKey" json:"id"`
+	Name      string `json:"name"`
+	Category  string `gorm:"index" json:"category"` // Grupo da TACO, ex.: "Frutas e derivados"
+	Nutrients        // Valores por 100 g
 }
 
 func MigrateFood(db *gorm.DB) error {
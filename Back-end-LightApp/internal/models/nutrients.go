@@ -0,0 +1,174 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Nutrients agrupa os valores nutricionais. Em Food os valores são por 100 g;
+// em MealItem são os valores absolutos da porção registrada.
+type Nutrients struct {
+	Calories float64 `json:"calories"`
+	Protein  float64 `json:"protein"`
+	Carbs    float64 `json:"carbs"`
+	Fat      float64 `json:"fat"`
+	Fiber    float64 `json:"fiber"`
+	Water    float64 `json:"water"` // Umidade: gramas de água (equivalente a ml)
+
+	Micronutrients `json:"micronutrients"`
+}
+
+// nutrientColumns lista as colunas de Nutrients, iguais em foods e meal_items
+// (as de Micronutrients são acrescentadas em micronutrients.go)
+var nutrientColumns = []string{"calories", "protein", "carbs", "fat", "fiber", "water"}
+
+// Add soma dois conjuntos de nutrientes
+func (n Nutrients) Add(other Nutrients) Nutrients {
+	return Nutrients{
+		Calories: n.Calories + other.Calories,
+		Protein:  n.Protein + other.Protein,
+		Carbs:    n.Carbs + other.Carbs,
+		Fat:      n.Fat + other.Fat,
+		Fiber:    n.Fiber + other.Fiber,
+		Water:    n.Water + other.Water,
+
+		Micronutrients: n.Micronutrients.add(other.Micronutrients),
+	}
+}
+
+// Sub retorna a diferença entre dois conjuntos de nutrientes
+func (n Nutrients) Sub(other Nutrients) Nutrients {
+	return n.Add(other.Scale(-1))
+}
+
+// Scale multiplica todos os nutrientes pelo fator informado
+func (n Nutrients) Scale(factor float64) Nutrients {
+	return Nutrients{
+		Calories: n.Calories * factor,
+		Protein:  n.Protein * factor,
+		Carbs:    n.Carbs * factor,
+		Fat:      n.Fat * factor,
+		Fiber:    n.Fiber * factor,
+		Water:    n.Water * factor,
+
+		Micronutrients: n.Micronutrients.scale(factor),
+	}
+}
+
+// NutrientsFor calcula os nutrientes de uma porção do alimento em gramas
+func (f Food) NutrientsFor(grams float64) Nutrients {
+	return f.Nutrients.Scale(grams / 100.0)
+}
+
+// SnapshotFrom grava no item os nutrientes atuais do alimento
+func (item *MealItem) SnapshotFrom(food Food) {
+	item.Nutrients = food.NutrientsFor(item.Amount)
+	now := time.Now()
+	item.SnapshotAt = &now
+}
+
+// MealItemRecalcFilter restringe quais itens são recalculados
+type MealItemRecalcFilter struct {
+	FoodID      uint
+	UserID      uint
+	OnlyMissing bool // Apenas itens sem snapshot (registrados antes do snapshot existir)
+}
+
+// RecalculateMealItemNutrients regrava o snapshot dos itens a partir do catálogo atual
+func RecalculateMealItemNutrients(db *gorm.DB, filter MealItemRecalcFilter) (int64, error) {
+	sets := make([]string, 0, len(nutrientColumns)+1)
+	for _, column := range nutrientColumns {
+		sets = append(sets, fmt.Sprintf("%s = foods.%s * meal_items.amount / 100.0", column, column))
+	}
+	sets = append(sets, "snapshot_at = NOW()")
+
+	conditions := []string{"foods.id = meal_items.food_id"}
+	var args []interface{}
+	if filter.FoodID != 0 {
+		conditions = append(conditions, "meal_items.food_id = ?")
+		args = append(args, filter.FoodID)
+	}
+	if filter.UserID != 0 {
+		conditions = append(conditions, "meal_items.meal_id IN (SELECT id FROM meals WHERE user_id = ?)")
+		args = append(args, filter.UserID)
+	}
+	if filter.OnlyMissing {
+		conditions = append(conditions, "meal_items.snapshot_at IS NULL")
+	}
+
+	sql := "UPDATE meal_items SET " + strings.Join(sets, ", ") +
+		" FROM foods WHERE " + strings.Join(conditions, " AND ")
+
+	result := db.Exec(sql, args...)
+	return result.RowsAffected, result.Error
+}
+
+// lateSnapshotColumns são as colunas criadas depois do snapshot dos itens:
+// fibras, água e micronutrientes
+func lateSnapshotColumns() []string {
+	return append([]string{"fiber", "water"}, micronutrientColumns...)
+}
+
+// FillLateSnapshotColumns completa os itens com snapshot gravado antes de
+// fibras, água e micronutrientes existirem: essas colunas ficaram zeradas e
+// os relatórios somavam zero. Só elas são preenchidas pelo catálogo atual;
+// calorias e macros do snapshot não mudam. Itens de alimentos sem nenhum
+// desses valores não são alcançados, então repetir não altera nada.
+// Retorna os usuários com itens alterados.
+func FillLateSnapshotColumns(db *gorm.DB) ([]uint, error) {
+	columns := lateSnapshotColumns()
+	sets := make([]string, len(columns))
+	itemEmpty := make([]string, len(columns))
+	foodFilled := make([]string, len(columns))
+	for i, column := range columns {
+		sets[i] = fmt.Sprintf("%s = foods.%s * meal_items.amount / 100.0", column, column)
+		itemEmpty[i] = "meal_items." + column + " = 0"
+		foodFilled[i] = "foods." + column + " <> 0"
+	}
+
+	sql := "WITH updated AS (UPDATE meal_items SET " + strings.Join(sets, ", ") +
+		" FROM foods WHERE foods.id = meal_items.food_id AND meal_items.snapshot_at IS NOT NULL" +
+		" AND " + strings.Join(itemEmpty, " AND ") +
+		" AND (" + strings.Join(foodFilled, " OR ") + ")" +
+		" RETURNING meal_items.meal_id)" +
+		" SELECT DISTINCT meals.user_id FROM meals JOIN updated ON updated.meal_id = meals.id"
+
+	userIDs := []uint{}
+	err := db.Raw(sql).Scan(&userIDs).Error
+	return userIDs, err
+}
+
+// AffectedUsers retorna os usuários com itens alcançados pelo filtro, para
+// refazer os totais diários depois do recálculo (nil significa todos)
+func (filter MealItemRecalcFilter) AffectedUsers(db *gorm.DB) ([]uint, error) {
+	if filter.UserID != 0 {
+		return []uint{filter.UserID}, nil
+	}
+	if filter.FoodID == 0 {
+		return nil, nil
+	}
+	userIDs := []uint{}
+	err := db.Table("meals").
+		Joins("JOIN meal_items ON meal_items.meal_id = meals.id").
+		Where("meal_items.food_id = ?", filter.FoodID).
+		Distinct().Pluck("meals.user_id", &userIDs).Error
+	return userIDs, err
+}
+
+// NutrientColumns retorna os nomes das colunas de nutrientes, na ordem de Columns
+func NutrientColumns() []string {
+	return append([]string(nil), nutrientColumns...)
+}
+
+// Columns retorna os valores na ordem de NutrientColumns
+func (n Nutrients) Columns() []float64 {
+	values := []float64{n.Calories, n.Protein, n.Carbs, n.Fat, n.Fiber, n.Water}
+	micronutrients := n.Micronutrients.Values()
+	for _, column := range micronutrientColumns {
+		values = append(values, micronutrients[column])
+	}
+	return values
+}
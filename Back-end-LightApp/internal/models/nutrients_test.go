@@ -0,0 +1,42 @@
+package models
+
+import (
+	"database/sql/driver"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFillLateSnapshotColumns(t *testing.T) {
+	stub := &stubConnector{
+		columns: []string{"user_id"},
+		rows:    [][]driver.Value{{int64(3)}, {int64(8)}},
+	}
+	db := openStub(t, stub)
+
+	userIDs, err := FillLateSnapshotColumns(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(userIDs, []uint{3, 8}) {
+		t.Errorf("usuários = %v", userIDs)
+	}
+	if len(stub.statements) != 1 {
+		t.Fatalf("%d instruções, esperada 1: %q", len(stub.statements), stub.statements)
+	}
+
+	statement := stub.statements[0]
+	for _, column := range lateSnapshotColumns() {
+		if !strings.Contains(statement, column+" = foods."+column) {
+			t.Errorf("coluna %s não preenchida: %s", column, statement)
+		}
+	}
+	for _, column := range []string{"calories", "protein", "carbs", "fat"} {
+		if strings.Contains(statement, column+" = foods.") {
+			t.Errorf("o snapshot de %s não deve mudar: %s", column, statement)
+		}
+	}
+	if !strings.Contains(statement, "snapshot_at IS NOT NULL") {
+		t.Errorf("itens sem snapshot ficam com RecalculateMealItemNutrients: %s", statement)
+	}
+}
@@ -0,0 +1,99 @@
+package models
+
+import "time"
+
+// Agrupamentos aceitos no resumo por período
+const (
+	SummaryGroupDay   = "day"
+	SummaryGroupWeek  = "week"
+	SummaryGroupMonth = "month"
+)
+
+// Maior período aceito no resumo, em dias
+const MaxSummaryDays = 366
+
+// IsValidSummaryGroup indica se o agrupamento é conhecido
+func IsValidSummaryGroup(group string) bool {
+	switch group {
+	case SummaryGroupDay, SummaryGroupWeek, SummaryGroupMonth:
+		return true
+	}
+	return false
+}
+
+// SummaryBucket é um ponto da série: um dia, uma semana (segunda a domingo)
+// ou um mês, recortado para dentro do período pedido
+type SummaryBucket struct {
+	Start      string    `json:"start"`
+	End        string    `json:"end"`
+	Days       int       `json:"days"`
+	LoggedDays int       `json:"logged_days"`
+	EmptyDays  int       `json:"empty_days"` // Dias sem nenhum item registrado
+	Total      Nutrients `json:"total"`
+	Average    Nutrients `json:"average"` // Média por dia com registro
+}
+
+// BuildSummaryBuckets monta a série ordenada de from até to (datas locais,
+// inclusive), contando também os dias sem registro
+func BuildSummaryBuckets(totals map[string]Nutrients, from, to time.Time, group string) []SummaryBucket {
+	buckets := []SummaryBucket{}
+	var current *SummaryBucket
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		start := bucketStart(day, group)
+		if start.Before(from) {
+			start = from
+		}
+		key := start.Format(DateLayout)
+		if current == nil || current.Start != key {
+			buckets = append(buckets, SummaryBucket{Start: key})
+			current = &buckets[len(buckets)-1]
+		}
+
+		current.End = day.Format(DateLayout)
+		current.Days++
+		if total, ok := totals[day.Format(DateLayout)]; ok {
+			current.LoggedDays++
+			current.Total = current.Total.Add(total)
+		} else {
+			current.EmptyDays++
+		}
+	}
+
+	for i := range buckets {
+		if buckets[i].LoggedDays > 0 {
+			buckets[i].Average = buckets[i].Total.Scale(1 / float64(buckets[i].LoggedDays))
+		}
+	}
+	return buckets
+}
+
+// MergeSummaryBuckets junta a série em um único resumo do período
+func MergeSummaryBuckets(buckets []SummaryBucket) SummaryBucket {
+	var merged SummaryBucket
+	for i, bucket := range buckets {
+		if i == 0 {
+			merged.Start = bucket.Start
+		}
+		merged.End = bucket.End
+		merged.Days += bucket.Days
+		merged.LoggedDays += bucket.LoggedDays
+		merged.EmptyDays += bucket.EmptyDays
+		merged.Total = merged.Total.Add(bucket.Total)
+	}
+	if merged.LoggedDays > 0 {
+		merged.Average = merged.Total.Scale(1 / float64(merged.LoggedDays))
+	}
+	return merged
+}
+
+// bucketStart retorna o primeiro dia do grupo da data
+func bucketStart(day time.Time, group string) time.Time {
+	switch group {
+	case SummaryGroupWeek:
+		offset := (int(day.Weekday()) + 6) % 7 // Semanas começam na segunda-feira
+		return day.AddDate(0, 0, -offset)
+	case SummaryGroupMonth:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+	}
+	return day
+}
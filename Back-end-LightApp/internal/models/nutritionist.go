@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// NutritionistAccess é a permissão dada pelo usuário (ClientID) a outra conta
+// (NutritionistID) para montar e acompanhar os seus planos alimentares
+type NutritionistAccess struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	ClientID       uint      `gorm:"uniqueIndex:idx_nutritionist_client" json:"client_id"`
+	NutritionistID uint      `gorm:"uniqueIndex:idx_nutritionist_client;index" json:"nutritionist_id"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+func MigrateNutritionistAccess(db *gorm.DB) error {
+	return db.AutoMigrate(&NutritionistAccess{})
+}
+
+// HasNutritionistAccess indica se o nutricionista tem acesso aos dados do cliente
+func HasNutritionistAccess(db *gorm.DB, nutritionistID, clientID uint) (bool, error) {
+	var count int64
+	err := db.Model(&NutritionistAccess{}).
+		Where("nutritionist_id = ? AND client_id = ?", nutritionistID, clientID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// AccessContact é o que um lado da permissão vê do outro
+type AccessContact struct {
+	AccessID  uint      `json:"access_id"`
+	UserID    uint      `json:"user_id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	GrantedAt time.Time `json:"granted_at"`
+}
+
+// ListAccessContacts lista as contas do outro lado das permissões do usuário:
+// os nutricionistas com acesso (asNutritionist falso) ou os clientes que
+// deram acesso a ele (asNutritionist verdadeiro)
+func ListAccessContacts(db *gorm.DB, userID uint, asNutritionist bool) ([]AccessContact, error) {
+	ownColumn, otherColumn := "client_id", "nutritionist_id"
+	if asNutritionist {
+		ownColumn, otherColumn = otherColumn, ownColumn
+	}
+
+	contacts := []AccessContact{}
+	err := db.Model(&NutritionistAccess{}).
+		Select("nutritionist_accesses.id AS access_id, users.id AS user_id, users.name, users.email, "+
+			"nutritionist_accesses.created_at AS granted_at").
+		Joins("JOIN users ON users.id = nutritionist_accesses."+otherColumn+" AND users.deleted_at IS NULL").
+		Where("nutritionist_accesses."+ownColumn+" = ?", userID).
+		Order("users.name").Scan(&contacts).Error
+	return contacts, err
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestListAccessContacts(t *testing.T) {
+	granted := time.Date(2026, 5, 4, 13, 30, 0, 0, time.UTC)
+	stub := &stubConnector{
+		columns: []string{"access_id", "user_id", "name", "email", "granted_at"},
+		rows:    [][]driver.Value{{int64(4), int64(9), "Ana", "ana@example.com", granted}},
+	}
+	db := openStub(t, stub)
+
+	for _, asNutritionist := range []bool{false, true} {
+		stub.statements = nil
+		contacts, err := ListAccessContacts(db, 2, asNutritionist)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(contacts) != 1 || contacts[0].UserID != 9 || contacts[0].Email != "ana@example.com" || !contacts[0].GrantedAt.Equal(granted) {
+			t.Errorf("contatos = %+v", contacts)
+		}
+
+		own, other := "client_id", "nutritionist_id"
+		if asNutritionist {
+			own, other = other, own
+		}
+		statement := stub.statements[0]
+		if !strings.Contains(statement, "users.id = nutritionist_accesses."+other) ||
+			!strings.Contains(statement, "nutritionist_accesses."+own+" = $1") {
+			t.Errorf("lado errado da permissão: %s", statement)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Recipe é uma receita do usuário: ingredientes do catálogo para o rendimento
+// informado em Servings
+type Recipe struct {
+	ID           uint         `gorm:"primaryKey" json:"id"`
+	UserID       uint         `gorm:"index" json:"user_id"`
+	Name         string       `json:"name"`
+	Servings     float64      `json:"servings"` // Porções que a receita rende
+	Instructions string       `json:"instructions"`
+	Items        []RecipeItem `gorm:"foreignKey:RecipeID;constraint:OnDelete:CASCADE" json:"items"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+}
+
+// RecipeItem é um ingrediente, com a quantidade usada na receita inteira
+type RecipeItem struct {
+	ID        uint    `gorm:"primaryKey" json:"id"`
+	RecipeID  uint    `gorm:"index" json:"recipe_id"`
+	FoodID    uint    `json:"food_id"`
+	Amount    float64 `json:"amount"` // Quantidade em gramas
+	Quantity  float64 `json:"quantity"`
+	Unit      string  `json:"unit"`
+	Nutrients         // Valores do ingrediente na quantidade da receita
+}
+
+func MigrateRecipe(db *gorm.DB) error {
+	return db.AutoMigrate(&Recipe{}, &RecipeItem{})
+}
+
+// Prepare valida a receita e calcula os nutrientes dos ingredientes pelo catálogo
+func (r *Recipe) Prepare(db *gorm.DB) error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		return errors.New("Informe o nome da receita")
+	}
+	if r.Servings == 0 {
+		r.Servings = 1
+	}
+	if r.Servings < 0 {
+		return errors.New("Rendimento da receita inválido")
+	}
+	if len(r.Items) == 0 {
+		return errors.New("Informe os ingredientes da receita")
+	}
+
+	for i := range r.Items {
+		item := &r.Items[i]
+		item.ID = 0
+		if item.Amount <= 0 {
+			return errors.New("Quantidade do ingrediente inválida")
+		}
+		var food Food
+		if err := db.First(&food, item.FoodID).Error; err != nil {
+			return errors.New("Ingrediente não encontrado no TACO")
+		}
+		if item.Unit == "" {
+			item.Unit = "g"
+		}
+		if item.Quantity == 0 {
+			item.Quantity = item.Amount
+		}
+		item.Nutrients = food.NutrientsFor(item.Amount)
+	}
+	return nil
+}
+
+// Totals soma os nutrientes da receita inteira
+func (r Recipe) Totals() Nutrients {
+	var total Nutrients
+	for _, item := range r.Items {
+		total = total.Add(item.Nutrients)
+	}
+	return total
+}
+
+// PerServing divide os nutrientes da receita pelo rendimento
+func (r Recipe) PerServing() Nutrients {
+	if r.Servings <= 0 {
+		return r.Totals()
+	}
+	return r.Totals().Scale(1 / r.Servings)
+}
@@ -4,5 +4,6 @@ import "gorm.io/gorm"
 
 type RevokedToken struct {
 	gorm.Model
-	Token string `json:"token" gorm:"unique"`
+	Token  string `json:"token" gorm:"unique"`
+	UserID uint   `json:"user_id" gorm:"index"` // Zero nos tokens revogados antes desta coluna
 }
@@ -0,0 +1,309 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Seções da lista de compras, na ordem em que aparecem
+const (
+	SectionProduce   = "hortifruti"
+	SectionMeat      = "carnes"
+	SectionEggs      = "ovos"
+	SectionDairy     = "laticínios"
+	SectionGrocery   = "mercearia"
+	SectionBeverages = "bebidas"
+	SectionOther     = "outros"
+)
+
+var shoppingSectionOrder = []string{
+	SectionProduce, SectionMeat, SectionEggs, SectionDairy, SectionGrocery, SectionBeverages, SectionOther,
+}
+
+// shoppingSections agrupa as categorias da TACO em seções de supermercado
+var shoppingSections = map[string]string{
+	"Verduras, hortaliças e derivados":      SectionProduce,
+	"Frutas e derivados":                    SectionProduce,
+	"Carnes e derivados":                    SectionMeat,
+	"Pescados e frutos do mar":              SectionMeat,
+	"Ovos e derivados":                      SectionEggs,
+	"Leite e derivados":                     SectionDairy,
+	"Cereais e derivados":                   SectionGrocery,
+	"Leguminosas e derivados":               SectionGrocery,
+	"Nozes e sementes":                      SectionGrocery,
+	"Gorduras e óleos":                      SectionGrocery,
+	"Produtos açucarados":                   SectionGrocery,
+	"Miscelâneas":                           SectionGrocery,
+	"Outros alimentos industrializados":     SectionGrocery,
+	"Alimentos preparados":                  SectionGrocery,
+	"Bebidas (alcoólicas e não alcoólicas)": SectionBeverages,
+}
+
+// Arredondamento para cima por seção (em g, ml ou unidades)
+var shoppingRounding = map[string]float64{
+	SectionProduce:   50,
+	SectionMeat:      100,
+	SectionEggs:      1,
+	SectionDairy:     100,
+	SectionGrocery:   100,
+	SectionBeverages: 250,
+	SectionOther:     50,
+}
+
+// Peso médio de um ovo, usado para comprar ovos em unidades
+const eggUnitGrams = 50.0
+
+// Estados de preparo que indicam alimento pronto na TACO
+var cookedStates = []string{
+	"cozido", "cozida", "grelhado", "grelhada", "assado", "assada",
+	"frito", "frita", "refogado", "refogada", "cozido/10minutos", "cozida/10minutos",
+}
+
+type ShoppingList struct {
+	ID        uint               `gorm:"primaryKey" json:"id"`
+	UserID    uint               `gorm:"index" json:"user_id"`
+	Name      string             `json:"name"`
+	PlanID    *uint              `json:"plan_id"`
+	FromDate  *time.Time         `json:"from_date"` // Período do plano; nulo em listas só de receitas
+	ToDate    *time.Time         `json:"to_date"`
+	RecipeIDs []uint             `gorm:"serializer:json;type:text" json:"recipe_ids"`
+	Items     []ShoppingListItem `gorm:"foreignKey:ListID;constraint:OnDelete:CASCADE" json:"items"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+type ShoppingListItem struct {
+	ID       uint    `gorm:"primaryKey" json:"id"`
+	ListID   uint    `gorm:"index" json:"list_id"`
+	FoodID   uint    `json:"food_id"` // Alimento a comprar (cru, quando há equivalente)
+	Name     string  `json:"name"`
+	Section  string  `json:"section"`
+	Grams    float64 `json:"grams"`    // Total necessário, já convertido para cru
+	Quantity float64 `json:"quantity"` // Quantidade arredondada para compra
+	Unit     string  `json:"unit"`     // g, ml ou unidade
+	Checked  bool    `gorm:"default:false" json:"checked"`
+}
+
+func MigrateShoppingList(db *gorm.DB) error {
+	return db.AutoMigrate(&ShoppingList{}, &ShoppingListItem{})
+}
+
+// AddPlanIngredients soma em gramsByFood os itens do plano entre from e to
+// (inclusive). Registros rápidos não entram na lista, pois não têm alimento.
+func AddPlanIngredients(gramsByFood map[uint]float64, plan MealPlan, from, to time.Time) {
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		for _, meal := range plan.MealsFor(day) {
+			for _, item := range meal.Items {
+				if item.FoodID != nil {
+					gramsByFood[*item.FoodID] += item.Amount
+				}
+			}
+		}
+	}
+}
+
+// AddRecipeIngredients soma em gramsByFood os ingredientes da receita
+// ajustados para o número de porções desejado (zero: o rendimento da receita)
+func AddRecipeIngredients(gramsByFood map[uint]float64, recipe Recipe, servings float64) {
+	factor := 1.0
+	if servings > 0 && recipe.Servings > 0 {
+		factor = servings / recipe.Servings
+	}
+	for _, item := range recipe.Items {
+		gramsByFood[item.FoodID] += item.Amount * factor
+	}
+}
+
+// BuildShoppingListItems converte as quantidades por alimento em itens de
+// compra: alimentos prontos viram o peso cru e tudo é arredondado para compra
+func BuildShoppingListItems(db *gorm.DB, gramsByFood map[uint]float64) ([]ShoppingListItem, error) {
+	if len(gramsByFood) == 0 {
+		return []ShoppingListItem{}, nil
+	}
+
+	ids := make([]uint, 0, len(gramsByFood))
+	for id := range gramsByFood {
+		ids = append(ids, id)
+	}
+	var foods []Food
+	if err := db.Where("id IN ?", ids).Find(&foods).Error; err != nil {
+		return nil, err
+	}
+
+	rawByName, err := rawCounterparts(db, foods)
+	if err != nil {
+		return nil, err
+	}
+
+	// Alimentos prontos e crus equivalentes são somados no mesmo item
+	byPurchase := make(map[uint]*ShoppingListItem)
+	for _, food := range foods {
+		purchase, grams := food, gramsByFood[food.ID]
+		if raw, ok := rawByName[food.ID]; ok {
+			purchase, grams = raw, grams*rawYield(food, raw)
+		}
+
+		item, ok := byPurchase[purchase.ID]
+		if !ok {
+			item = &ShoppingListItem{FoodID: purchase.ID, Name: purchase.Name, Section: ShoppingSection(purchase.Category)}
+			byPurchase[purchase.ID] = item
+		}
+		item.Grams += grams
+	}
+
+	items := make([]ShoppingListItem, 0, len(byPurchase))
+	for _, item := range byPurchase {
+		item.Quantity, item.Unit = purchaseQuantity(item.Section, item.Grams)
+		item.Grams = math.Round(item.Grams)
+		items = append(items, *item)
+	}
+	sortShoppingItems(items)
+	return items, nil
+}
+
+// ShoppingSection retorna a seção de supermercado de uma categoria da TACO
+func ShoppingSection(category string) string {
+	if section, ok := shoppingSections[category]; ok {
+		return section
+	}
+	return SectionOther
+}
+
+// rawCounterparts encontra a versão crua dos alimentos prontos, pelo nome da TACO
+// ("Arroz, tipo 1, cozido" -> "Arroz, tipo 1, cru")
+func rawCounterparts(db *gorm.DB, foods []Food) (map[uint]Food, error) {
+	candidates := make(map[uint][]string)
+	var names []string
+	for _, food := range foods {
+		base, ok := cookedBaseName(food.Name)
+		if !ok {
+			continue
+		}
+		candidates[food.ID] = []string{base + ", cru", base + ", crua"}
+		names = append(names, candidates[food.ID]...)
+	}
+	if len(names) == 0 {
+		return map[uint]Food{}, nil
+	}
+
+	var raws []Food
+	if err := db.Where("name IN ?", names).Find(&raws).Error; err != nil {
+		return nil, err
+	}
+	rawsByName := make(map[string]Food, len(raws))
+	for _, raw := range raws {
+		rawsByName[raw.Name] = raw
+	}
+
+	result := make(map[uint]Food)
+	for foodID, options := range candidates {
+		for _, name := range options {
+			if raw, ok := rawsByName[name]; ok {
+				result[foodID] = raw
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+// cookedBaseName remove o estado de preparo do fim do nome da TACO
+func cookedBaseName(name string) (string, bool) {
+	index := strings.LastIndex(name, ",")
+	if index < 0 {
+		return "", false
+	}
+	last := strings.ToLower(strings.TrimSpace(name[index+1:]))
+	for _, state := range cookedStates {
+		if last == state {
+			return strings.TrimSpace(name[:index]), true
+		}
+	}
+	return "", false
+}
+
+// rawYield converte o peso pronto em peso cru conservando a matéria seca
+// (o que muda no preparo é principalmente a água). Sem umidade na base, o
+// peso não é convertido.
+func rawYield(cooked, raw Food) float64 {
+	if cooked.Water <= 0 || raw.Water <= 0 || raw.Water >= 100 {
+		return 1
+	}
+	factor := (100 - cooked.Water) / (100 - raw.Water)
+	return math.Max(0.25, math.Min(factor, 2))
+}
+
+// purchaseQuantity arredonda a quantidade para cima no passo da seção
+func purchaseQuantity(section string, grams float64) (float64, string) {
+	step := shoppingRounding[section]
+	switch section {
+	case SectionEggs:
+		return math.Ceil(grams / eggUnitGrams), "unidade"
+	case SectionBeverages:
+		return math.Ceil(grams/step) * step, "ml"
+	default:
+		return math.Ceil(grams/step) * step, "g"
+	}
+}
+
+func sortShoppingItems(items []ShoppingListItem) {
+	position := make(map[string]int, len(shoppingSectionOrder))
+	for i, section := range shoppingSectionOrder {
+		position[section] = i
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Section != items[j].Section {
+			return position[items[i].Section] < position[items[j].Section]
+		}
+		return items[i].Name < items[j].Name
+	})
+}
+
+// FormatQuantity apresenta a quantidade de compra ("1,5 kg", "300 g", "6 unidades")
+func (item ShoppingListItem) FormatQuantity() string {
+	switch item.Unit {
+	case "unidade":
+		if item.Quantity == 1 {
+			return "1 unidade"
+		}
+		return fmt.Sprintf("%.0f unidades", item.Quantity)
+	case "ml":
+		if item.Quantity >= 1000 {
+			return strings.Replace(fmt.Sprintf("%g l", item.Quantity/1000), ".", ",", 1)
+		}
+		return fmt.Sprintf("%.0f ml", item.Quantity)
+	default:
+		if item.Quantity >= 1000 {
+			return strings.Replace(fmt.Sprintf("%g kg", item.Quantity/1000), ".", ",", 1)
+		}
+		return fmt.Sprintf("%.0f g", item.Quantity)
+	}
+}
+
+// Text exporta a lista em texto simples, pronta para compartilhar; o período
+// é mostrado no fuso do usuário
+func (l ShoppingList) Text(loc *time.Location) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Lista de compras - %s\n", l.Name)
+	if l.FromDate != nil && l.ToDate != nil {
+		fmt.Fprintf(&b, "%s a %s\n", l.FromDate.In(loc).Format("02/01/2006"), l.ToDate.In(loc).Format("02/01/2006"))
+	}
+
+	section := ""
+	for _, item := range l.Items {
+		if item.Section != section {
+			section = item.Section
+			fmt.Fprintf(&b, "\n%s\n", strings.ToUpper(section))
+		}
+		mark := " "
+		if item.Checked {
+			mark = "x"
+		}
+		fmt.Fprintf(&b, "[%s] %s - %s\n", mark, item.Name, item.FormatQuantity())
+	}
+	return b.String()
+}
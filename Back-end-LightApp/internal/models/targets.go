@@ -0,0 +1,194 @@
+package models
+
+import (
+	"errors"
+	"math"
+)
+
+// Multiplicadores de calorias por objetivo
+var goalCalorieFactors = map[string]float64{
+	"lose": 0.8,
+	"gain": 1.15,
+}
+
+// Proteína padrão em g/kg de peso corporal, por objetivo
+var goalProteinPerKg = map[string]float64{
+	"lose": 1.6,
+	"gain": 1.6,
+}
+
+// Valores padrão das metas de macronutrientes
+const (
+	defaultProteinPerKg = 1.2
+	defaultFatPercent   = 30.0 // Percentual das calorias
+	fiberPer1000kcal    = 14.0 // Ingestão adequada de fibra (IOM)
+)
+
+// Calorias por grama de cada macronutriente
+const (
+	KcalPerGramProtein = 4.0
+	KcalPerGramCarbs   = 4.0
+	KcalPerGramFat     = 9.0
+)
+
+// ErrIncompleteProfile indica que faltam dados para calcular as metas
+var ErrIncompleteProfile = errors.New("Peso, altura, idade, sexo e nível de atividade precisam ser cadastrados")
+
+// EnergyEstimate é o gasto estimado do usuário e a meta calórica do objetivo
+type EnergyEstimate struct {
+	TMB          float64      `json:"tmb"`
+	TDEE         float64      `json:"tdee"`
+	TDEESource   string       `json:"tdee_source"`  // formula ou adaptive
+	FormulaTDEE  float64      `json:"formula_tdee"` // TMB × fator de atividade
+	GoalCalories float64      `json:"goal_calories"`
+	Equation     string       `json:"equation"`
+	EquationName string       `json:"equation_name"`
+	Inputs       EnergyInputs `json:"inputs"`
+}
+
+// NutritionTargets são as metas diárias (kcal e gramas)
+type NutritionTargets struct {
+	Calories float64 `json:"calories"`
+	Protein  float64 `json:"protein"`
+	Carbs    float64 `json:"carbs"`
+	Fat      float64 `json:"fat"`
+	Fiber    float64 `json:"fiber"`
+}
+
+// Origem do TDEE usado na meta do objetivo
+const (
+	TDEESourceFormula  = "formula"
+	TDEESourceAdaptive = "adaptive"
+)
+
+// EstimateEnergy calcula TMB, TDEE e a meta do objetivo com a equação
+// escolhida no perfil. Sem escolha, usa Katch-McArdle quando o percentual de
+// gordura é conhecido (ApplyBodyFat) e Harris-Benedict revisada nos demais casos.
+// Com o gasto adaptativo carregado (ApplyAdaptiveTDEE), ele substitui o da fórmula.
+func (u User) EstimateEnergy() (EnergyEstimate, error) {
+	key := u.EnergyEquation
+	if key == "" {
+		key = EquationHarrisBenedict
+		if u.BodyFat != nil {
+			key = EquationKatchMcArdle
+		}
+	}
+	return u.EstimateEnergyWith(key)
+}
+
+// EstimateEnergyWith calcula o gasto com uma equação específica
+func (u User) EstimateEnergyWith(key string) (EnergyEstimate, error) {
+	equation, ok := EnergyEquations[key]
+	if !ok {
+		return EnergyEstimate{}, ErrInvalidEnergyEquation
+	}
+	inputs, err := u.energyInputs(equation)
+	if err != nil {
+		return EnergyEstimate{}, err
+	}
+
+	tmb := equation.bmr(inputs)
+	formulaTDEE := tmb * inputs.ActivityMultiplier
+	tdee, source := formulaTDEE, TDEESourceFormula
+	if u.AdaptiveTDEE != nil {
+		tdee, source = *u.AdaptiveTDEE, TDEESourceAdaptive
+	}
+	goal := tdee
+	if factor, ok := goalCalorieFactors[u.Goal]; ok {
+		goal = tdee * factor
+	}
+	return EnergyEstimate{
+		TMB:          tmb,
+		TDEE:         tdee,
+		TDEESource:   source,
+		FormulaTDEE:  formulaTDEE,
+		GoalCalories: goal,
+		Equation:     key,
+		EquationName: equation.Name,
+		Inputs:       inputs,
+	}, nil
+}
+
+// defaultMacroTargets é a distribuição usada quando o usuário não definiu
+// metas: proteína por kg de peso conforme o objetivo, gordura em percentual
+// das calorias e carboidratos com o restante
+func (u User) defaultMacroTargets() MacroTargets {
+	proteinPerKg := defaultProteinPerKg
+	if value, ok := goalProteinPerKg[u.Goal]; ok {
+		proteinPerKg = value
+	}
+	return MacroTargets{
+		Protein: MacroTarget{Mode: MacroModePerKg, Value: proteinPerKg},
+		Fat:     MacroTarget{Mode: MacroModePercent, Value: defaultFatPercent},
+		Carbs:   MacroTarget{Mode: MacroModeRemainder},
+	}
+}
+
+// EffectiveMacroTargets retorna a configuração do usuário ou a padrão
+func (u User) EffectiveMacroTargets() MacroTargets {
+	if u.MacroTargets != nil {
+		return *u.MacroTargets
+	}
+	return u.defaultMacroTargets()
+}
+
+// NutritionTargets calcula as metas diárias em gramas. As calorias vêm da
+// configuração, quando fixadas, ou do objetivo do perfil.
+func (u User) NutritionTargets() (NutritionTargets, error) {
+	return u.NutritionTargetsWith(u.EffectiveMacroTargets())
+}
+
+// NutritionTargetsWith calcula as metas com outra configuração, para validar
+// antes de gravar
+func (u User) NutritionTargetsWith(config MacroTargets) (NutritionTargets, error) {
+	calories := config.Calories
+	if calories == 0 {
+		energy, err := u.EstimateEnergy()
+		if err != nil {
+			return NutritionTargets{}, err
+		}
+		calories = energy.GoalCalories
+	}
+	return config.Resolve(calories, u.Weight)
+}
+
+// TargetProgress compara o consumido com a meta. Remaining fica negativo
+// quando a meta foi ultrapassada.
+type TargetProgress struct {
+	Target    float64 `json:"target"`
+	Consumed  float64 `json:"consumed"`
+	Remaining float64 `json:"remaining"`
+	Percent   float64 `json:"percent"`
+}
+
+// DailyProgress é o progresso do dia em cada meta
+type DailyProgress struct {
+	Calories TargetProgress `json:"calories"`
+	Protein  TargetProgress `json:"protein"`
+	Carbs    TargetProgress `json:"carbs"`
+	Fat      TargetProgress `json:"fat"`
+	Fiber    TargetProgress `json:"fiber"`
+}
+
+// BuildDailyProgress compara os nutrientes consumidos com as metas
+func BuildDailyProgress(targets NutritionTargets, consumed Nutrients) DailyProgress {
+	return DailyProgress{
+		Calories: newTargetProgress(targets.Calories, consumed.Calories),
+		Protein:  newTargetProgress(targets.Protein, consumed.Protein),
+		Carbs:    newTargetProgress(targets.Carbs, consumed.Carbs),
+		Fat:      newTargetProgress(targets.Fat, consumed.Fat),
+		Fiber:    newTargetProgress(targets.Fiber, consumed.Fiber),
+	}
+}
+
+func newTargetProgress(target, consumed float64) TargetProgress {
+	progress := TargetProgress{
+		Target:    math.Round(target*10) / 10,
+		Consumed:  math.Round(consumed*10) / 10,
+		Remaining: math.Round((target-consumed)*10) / 10,
+	}
+	if target > 0 {
+		progress.Percent = math.Round(consumed/target*1000) / 10
+	}
+	return progress
+}
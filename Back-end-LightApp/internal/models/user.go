@@ -1,16 +1,82 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DefaultTimeZone é usado quando o usuário não informou o fuso horário
+const DefaultTimeZone = "America/Sao_Paulo"
 
 type User struct {
 	gorm.Model
-	Name          string  `json:"name"`
-	Email         string  `json:"email" gorm:"unique"`
-	Password      string  `json:"password"`
-	Weight        float64 `json:"weight"`
-	Height        float64 `json:"height"`
-	Age           int     `json:"age"`
-	Gender        string  `json:"gender"`
-	ActivityLevel float64 `json:"activity_level"`
-	Goal          string  `json:"goal"`
+	Name            string        `json:"name"`
+	Email           string        `json:"email" gorm:"unique"`
+	Password        string        `json:"password"`
+	Weight          float64       `json:"weight"`
+	Height          float64       `json:"height"`
+	Age             int           `json:"age"`
+	Gender          string        `json:"gender"`            // Identidade de gênero, texto livre
+	Sex             string        `json:"sex"`               // Sexo para equações e referências: male ou female
+	ActivityLevel   float64       `json:"activity_level"`    // Fator do preset em Activity
+	Activity        string        `json:"activity"`          // Chave de ActivityPresets
+	EnergyEquation  string        `json:"energy_equation"`   // Chave de EnergyEquations; vazio: automática
+	UseAdaptiveTDEE bool          `json:"use_adaptive_tdee"` // Metas pelo gasto estimado dos registros
+	Goal            string        `json:"goal"`
+	WaterGoalML     float64       `json:"water_goal_ml"`                                  // Zero: meta derivada do peso
+	TimeZone        string        `json:"time_zone"`                                      // Nome IANA, ex.: America/Sao_Paulo
+	MacroTargets    *MacroTargets `gorm:"serializer:json;type:text" json:"macro_targets"` // Nulo: metas padrão do objetivo
+	IsAdmin         bool          `json:"-" gorm:"default:false"`
+
+	DeletionScheduledAt *time.Time `json:"-" gorm:"index"` // Exclusão pedida: os dados são apagados nesta data
+
+	BodyFat      *float64 `json:"-" gorm:"-"` // % de gordura atual, carregado por ApplyBodyFat
+	AdaptiveTDEE *float64 `json:"-" gorm:"-"` // Gasto adaptativo, carregado por ApplyAdaptiveTDEE
+}
+
+func MigrateUser(db *gorm.DB) error {
+	return db.AutoMigrate(&User{}, &RevokedToken{})
+}
+
+// Location retorna o fuso horário do usuário (padrão: America/Sao_Paulo)
+func (u User) Location() *time.Location {
+	name := u.TimeZone
+	if name == "" {
+		name = DefaultTimeZone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// isMale indica se as fórmulas devem usar os coeficientes masculinos
+func (u User) isMale() bool {
+	return u.Sex == SexMale
+}
+
+// IMC calcula o índice de massa corporal e a classificação
+func (u User) IMC() (float64, string, error) {
+	if u.Weight == 0 || u.Height == 0 {
+		return 0, "", errors.New("Peso e altura precisam ser cadastrados")
+	}
+
+	heightInMeters := u.Height / 100.0
+	imc := u.Weight / (heightInMeters * heightInMeters)
+
+	var status string
+	switch {
+	case imc < 18.5:
+		status = "Abaixo do peso"
+	case imc < 25:
+		status = "Peso normal"
+	case imc < 30:
+		status = "Sobrepeso"
+	default:
+		status = "Obesidade"
+	}
+	return imc, status, nil
 }
@@ -0,0 +1,144 @@
+package models
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Limites aceitos para uma pesagem, em kg
+const (
+	MinWeightKg = 20.0
+	MaxWeightKg = 400.0
+)
+
+// WeightTrendAlpha é o fator de suavização diário da média móvel exponencial
+// (10% da diferença por dia, como no Hacker's Diet)
+const WeightTrendAlpha = 0.1
+
+// WeightEntry é uma pesagem do usuário. User.Weight guarda sempre a mais recente.
+type WeightEntry struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	UserID     uint      `gorm:"index:idx_weight_user_measured" json:"user_id"`
+	Weight     float64   `json:"weight"` // kg
+	MeasuredAt time.Time `gorm:"index:idx_weight_user_measured" json:"measured_at"`
+	Note       string    `json:"note"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func MigrateWeight(db *gorm.DB) error {
+	return db.AutoMigrate(&WeightEntry{})
+}
+
+// ValidateWeight confere se o peso está entre os limites aceitos
+func ValidateWeight(weight float64) error {
+	if weight < MinWeightKg || weight > MaxWeightKg {
+		return errors.New("Peso inválido (entre 20 e 400 kg)")
+	}
+	return nil
+}
+
+// LatestWeight retorna a pesagem mais recente do usuário (nil se não houver)
+func LatestWeight(db *gorm.DB, userID uint) (*WeightEntry, error) {
+	var entries []WeightEntry
+	if err := db.Where("user_id = ?", userID).Order("measured_at DESC, id DESC").Limit(1).Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	return &entries[0], nil
+}
+
+// SyncUserWeight grava em User.Weight a pesagem mais recente. Sem pesagens, o
+// peso do perfil é mantido.
+func SyncUserWeight(db *gorm.DB, userID uint) error {
+	latest, err := LatestWeight(db, userID)
+	if err != nil || latest == nil {
+		return err
+	}
+	return db.Model(&User{}).Where("id = ?", userID).Update("weight", latest.Weight).Error
+}
+
+// BackfillWeightEntries cria a primeira pesagem dos usuários que têm peso no
+// perfil e nenhuma pesagem registrada, na data da última atualização do perfil
+func BackfillWeightEntries(db *gorm.DB) (int64, error) {
+	result := db.Exec(`INSERT INTO weight_entries (user_id, weight, measured_at, note, created_at, updated_at)
+		SELECT id, weight, updated_at, '', NOW(), NOW() FROM users
+		WHERE weight > 0 AND deleted_at IS NULL
+		AND NOT EXISTS (SELECT 1 FROM weight_entries WHERE weight_entries.user_id = users.id)`)
+	return result.RowsAffected, result.Error
+}
+
+// WeightTrendPoint é o peso médio de um dia com pesagem e a tendência naquele dia
+type WeightTrendPoint struct {
+	Date   string  `json:"date"`
+	Weight float64 `json:"weight"` // Média das pesagens do dia
+	Trend  float64 `json:"trend"`
+}
+
+// WeightTrend calcula a média móvel exponencial das pesagens, um ponto por
+// dia com pesagem no fuso do usuário. Dias sem pesagem aplicam a suavização
+// acumulada: depois de n dias, o peso novo pesa 1-(1-alfa)^n. As pesagens
+// devem incluir as anteriores ao período exibido, para a tendência partir
+// de um valor estável.
+func WeightTrend(entries []WeightEntry, loc *time.Location) []WeightTrendPoint {
+	type day struct {
+		date  time.Time
+		sum   float64
+		count int
+	}
+	byDate := map[string]*day{}
+	for _, entry := range entries {
+		at := entry.MeasuredAt.In(loc)
+		key := at.Format(DateLayout)
+		if byDate[key] == nil {
+			byDate[key] = &day{date: time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, loc)}
+		}
+		byDate[key].sum += entry.Weight
+		byDate[key].count++
+	}
+
+	keys := make([]string, 0, len(byDate))
+	for key := range byDate {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	points := make([]WeightTrendPoint, 0, len(keys))
+	var previous time.Time
+	var trend float64
+	for i, key := range keys {
+		d := byDate[key]
+		weight := d.sum / float64(d.count)
+		if i == 0 {
+			trend = weight
+		} else {
+			days := math.Round(d.date.Sub(previous).Hours() / 24)
+			alpha := 1 - math.Pow(1-WeightTrendAlpha, days)
+			trend += alpha * (weight - trend)
+		}
+		previous = d.date
+		points = append(points, WeightTrendPoint{
+			Date:   key,
+			Weight: math.Round(weight*100) / 100,
+			Trend:  math.Round(trend*100) / 100,
+		})
+	}
+	return points
+}
+
+// ApplyLatestWeight usa no perfil a pesagem mais recente e a retorna (nil se
+// não houver pesagens, mantendo o peso do cadastro)
+func (u *User) ApplyLatestWeight(db *gorm.DB) (*WeightEntry, error) {
+	latest, err := LatestWeight(db, u.ID)
+	if err != nil || latest == nil {
+		return nil, err
+	}
+	u.Weight = latest.Weight
+	return latest, nil
+}
@@ -0,0 +1,76 @@
+package pdf
+
+import "unicode/utf8"
+
+// Larguras da Helvetica (métricas AFM, em milésimos do tamanho da fonte) dos
+// caracteres 32 a 126. A Helvetica-Bold é aproximada com um acréscimo fixo,
+// suficiente para alinhar colunas.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+const boldWidthFactor = 1.06
+
+// Caracteres fora do Latin-1 que existem na WinAnsiEncoding
+var winAnsiExtras = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '‘': 0x91, '’': 0x92,
+	'“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '™': 0x99,
+}
+
+// Letras acentuadas medem como a letra base
+var accentBase = map[byte]byte{}
+
+func init() {
+	groups := map[byte]string{
+		'A': "ÀÁÂÃÄÅ", 'C': "Ç", 'E': "ÈÉÊË", 'I': "ÌÍÎÏ", 'N': "Ñ", 'O': "ÒÓÔÕÖ", 'U': "ÙÚÛÜ",
+		'a': "àáâãäå", 'c': "ç", 'e': "èéêë", 'i': "ìíîï", 'n': "ñ", 'o': "òóôõö", 'u': "ùúûü",
+	}
+	for base, letters := range groups {
+		for _, letter := range letters {
+			accentBase[byte(letter)] = base
+		}
+	}
+}
+
+// encode converte UTF-8 para WinAnsiEncoding; o que não existe vira "?"
+func encode(text string) string {
+	out := make([]byte, 0, len(text))
+	for len(text) > 0 {
+		r, size := utf8.DecodeRuneInString(text)
+		text = text[size:]
+		switch {
+		case r < 0x80 || (r >= 0xA0 && r <= 0xFF):
+			out = append(out, byte(r))
+		case winAnsiExtras[r] != 0:
+			out = append(out, winAnsiExtras[r])
+		default:
+			out = append(out, '?')
+		}
+	}
+	return string(out)
+}
+
+// TextWidth estima a largura do texto em pontos
+func TextWidth(text string, size float64, bold bool) float64 {
+	total := 0
+	for _, c := range []byte(encode(text)) {
+		if base, ok := accentBase[c]; ok {
+			c = base
+		}
+		if c >= 32 && c <= 126 {
+			total += helveticaWidths[c-32]
+		} else {
+			total += 556
+		}
+	}
+	width := float64(total) * size / 1000
+	if bold {
+		width *= boldWidthFactor
+	}
+	return width
+}
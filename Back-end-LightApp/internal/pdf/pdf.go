@@ -0,0 +1,168 @@
+// Package pdf gera documentos PDF simples em Go puro: texto nas fontes padrão
+// Helvetica, linhas, retângulos e caminhos. É o suficiente para relatórios
+// com tabelas e gráficos, sem navegador nem bibliotecas externas.
+//
+// As coordenadas são em pontos (1/72 pol.) a partir do canto superior
+// esquerdo da página; a conversão para o sistema do PDF é feita aqui.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Tamanho da página A4, em pontos
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+// Color é uma cor RGB com componentes de 0 a 255
+type Color struct{ R, G, B uint8 }
+
+// Document é um PDF em construção
+type Document struct {
+	Title string
+	pages []*Page
+}
+
+// Page acumula os comandos de desenho de uma página
+type Page struct {
+	content bytes.Buffer
+}
+
+func New(title string) *Document {
+	return &Document{Title: title}
+}
+
+// AddPage acrescenta uma página A4 em branco
+func (d *Document) AddPage() *Page {
+	page := &Page{}
+	d.pages = append(d.pages, page)
+	return page
+}
+
+// PageCount retorna o número de páginas
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+// Text escreve o texto com a linha de base em (x, y)
+func (p *Page) Text(x, y, size float64, bold bool, color Color, text string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(&p.content, "BT /%s %.2f Tf %s rg %.2f %.2f Td (%s) Tj ET\n",
+		font, size, rgb(color), x, PageHeight-y, escape(encode(text)))
+}
+
+// TextRight escreve o texto alinhado à direita em x
+func (p *Page) TextRight(x, y, size float64, bold bool, color Color, text string) {
+	p.Text(x-TextWidth(text, size, bold), y, size, bold, color, text)
+}
+
+// TextCenter escreve o texto centralizado em x
+func (p *Page) TextCenter(x, y, size float64, bold bool, color Color, text string) {
+	p.Text(x-TextWidth(text, size, bold)/2, y, size, bold, color, text)
+}
+
+// Rect desenha um retângulo preenchido com o canto superior esquerdo em (x, y)
+func (p *Page) Rect(x, y, w, h float64, fill Color) {
+	fmt.Fprintf(&p.content, "%s rg %.2f %.2f %.2f %.2f re f\n", rgb(fill), x, PageHeight-y-h, w, h)
+}
+
+// Line desenha uma linha
+func (p *Page) Line(x1, y1, x2, y2, width float64, color Color) {
+	fmt.Fprintf(&p.content, "%s RG %.2f w %.2f %.2f m %.2f %.2f l S\n",
+		rgb(color), width, x1, PageHeight-y1, x2, PageHeight-y2)
+}
+
+// DashedLine desenha uma linha tracejada, usada para metas nos gráficos
+func (p *Page) DashedLine(x1, y1, x2, y2, width float64, color Color) {
+	fmt.Fprintf(&p.content, "q [4 3] 0 d %s RG %.2f w %.2f %.2f m %.2f %.2f l S Q\n",
+		rgb(color), width, x1, PageHeight-y1, x2, PageHeight-y2)
+}
+
+// Polyline liga os pontos em sequência; pontos são pares (x, y)
+func (p *Page) Polyline(points [][2]float64, width float64, color Color) {
+	if len(points) < 2 {
+		return
+	}
+	fmt.Fprintf(&p.content, "%s RG %.2f w 1 j ", rgb(color), width)
+	for i, point := range points {
+		operator := "l"
+		if i == 0 {
+			operator = "m"
+		}
+		fmt.Fprintf(&p.content, "%.2f %.2f %s ", point[0], PageHeight-point[1], operator)
+	}
+	p.content.WriteString("S\n")
+}
+
+// WriteTo grava o documento completo
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	var out bytes.Buffer
+	var offsets []int
+
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// 1: catálogo, 2: árvore de páginas, 3 e 4: fontes, 5: informações.
+	// Cada página usa dois objetos: a página e o seu conteúdo.
+	const firstPage = 6
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+i*2)
+	}
+
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	object(fmt.Sprintf("<< /Title (%s) /Producer (LightApp) >>", escape(encode(d.Title))))
+
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			PageWidth, PageHeight, firstPage+i*2+1))
+
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		if _, err := zw.Write(page.content.Bytes()); err != nil {
+			return 0, err
+		}
+		if err := zw.Close(); err != nil {
+			return 0, err
+		}
+		object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream",
+			compressed.Len(), compressed.Bytes()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	n, err := w.Write(out.Bytes())
+	return int64(n), err
+}
+
+func rgb(c Color) string {
+	return fmt.Sprintf("%.3f %.3f %.3f", float64(c.R)/255, float64(c.G)/255, float64(c.B)/255)
+}
+
+// escape protege os caracteres especiais das strings do PDF
+func escape(text string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`, "\r", " ", "\n", " ")
+	return replacer.Replace(text)
+}
@@ -0,0 +1,494 @@
+// Package report monta os relatórios para compartilhar fora do app
+package report
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/juliapinheiro42/LightApp/internal/dri"
+	"github.com/juliapinheiro42/LightApp/internal/models"
+	"github.com/juliapinheiro42/LightApp/internal/pdf"
+)
+
+// NutritionReport reúne os dados do relatório para o nutricionista. Os campos
+// opcionais ficam nulos ou vazios quando o perfil não permite calculá-los.
+type NutritionReport struct {
+	User             models.User
+	IMC              float64
+	IMCStatus        string
+	From, To         time.Time
+	GeneratedAt      time.Time
+	Targets          *models.NutritionTargets
+	Days             []models.SummaryBucket // Um por dia do período, com os dias sem registro
+	TopFoods         []models.FoodEnergy
+	Micronutrients   []dri.Assessment
+	ReferenceVersion string
+}
+
+// Cores e medidas do layout
+var (
+	colorText    = pdf.Color{R: 31, G: 41, B: 55}
+	colorMuted   = pdf.Color{R: 107, G: 114, B: 128}
+	colorAccent  = pdf.Color{R: 16, G: 185, B: 129}
+	colorProtein = pdf.Color{R: 16, G: 185, B: 129}
+	colorCarbs   = pdf.Color{R: 59, G: 130, B: 246}
+	colorFat     = pdf.Color{R: 239, G: 68, B: 68}
+	colorTarget  = pdf.Color{R: 245, G: 158, B: 11}
+	colorGrid    = pdf.Color{R: 229, G: 231, B: 235}
+	colorStripe  = pdf.Color{R: 243, G: 244, B: 246}
+	colorWhite   = pdf.Color{R: 255, G: 255, B: 255}
+)
+
+const (
+	margin    = 40.0
+	rowHeight = 16.0
+)
+
+// Situações dos micronutrientes em português
+var statusLabels = map[string]string{
+	dri.StatusLikelyInadequate:   "Provável deficiência",
+	dri.StatusPossiblyInadequate: "Possivelmente insuficiente",
+	dri.StatusAdequate:           "Adequado",
+	dri.StatusBelowAI:            "Abaixo da AI",
+	dri.StatusAboveUL:            "Acima do UL",
+}
+
+var goalLabels = map[string]string{
+	"lose": "Perder peso",
+	"gain": "Ganhar peso",
+}
+
+// WritePDF gera o relatório em PDF
+func WritePDF(w io.Writer, r NutritionReport) error {
+	l := &layout{doc: pdf.New("Relatório nutricional - " + r.User.Name)}
+	l.newPage()
+
+	writeCover(l, r)
+	writeCharts(l, r)
+	writeDailyTable(l, r)
+	writeTopFoods(l, r)
+	writeMicronutrients(l, r)
+	l.footers(r.GeneratedAt)
+
+	_, err := l.doc.WriteTo(w)
+	return err
+}
+
+// layout controla a posição vertical e as quebras de página
+type layout struct {
+	doc   *pdf.Document
+	pages []*pdf.Page
+	page  *pdf.Page
+	y     float64
+}
+
+func (l *layout) newPage() {
+	l.page = l.doc.AddPage()
+	l.pages = append(l.pages, l.page)
+	l.y = margin
+}
+
+// ensure abre uma nova página se não couber a altura pedida
+func (l *layout) ensure(height float64) {
+	if l.y+height > pdf.PageHeight-margin-20 {
+		l.newPage()
+	}
+}
+
+func (l *layout) heading(text string) {
+	l.ensure(60)
+	l.y += 18
+	l.page.Text(margin, l.y, 15, true, colorText, text)
+	l.y += 6
+	l.page.Line(margin, l.y, pdf.PageWidth-margin, l.y, 1, colorAccent)
+	l.y += 16
+}
+
+func (l *layout) paragraph(text string) {
+	l.ensure(rowHeight)
+	l.page.Text(margin, l.y, 9, false, colorMuted, text)
+	l.y += 14
+}
+
+// table desenha uma tabela com cabeçalho repetido a cada página. A primeira
+// coluna é alinhada à esquerda e as demais à direita.
+func (l *layout) table(widths []float64, header []string, rows [][]string, muted func(row int) bool) {
+	drawHeader := func() {
+		l.page.Rect(margin, l.y, sum(widths), rowHeight+2, colorText)
+		l.cells(widths, header, true, colorWhite)
+		l.y += rowHeight + 2
+	}
+
+	l.ensure(rowHeight * 3)
+	drawHeader()
+	for i, row := range rows {
+		if l.y+rowHeight > pdf.PageHeight-margin-20 {
+			l.newPage()
+			drawHeader()
+		}
+		if i%2 == 1 {
+			l.page.Rect(margin, l.y, sum(widths), rowHeight, colorStripe)
+		}
+		color := colorText
+		if muted != nil && muted(i) {
+			color = colorMuted
+		}
+		l.cells(widths, row, false, color)
+		l.y += rowHeight
+	}
+	l.y += 10
+}
+
+func (l *layout) cells(widths []float64, values []string, bold bool, color pdf.Color) {
+	x := margin
+	baseline := l.y + rowHeight - 4.5
+	for i, value := range values {
+		if i == 0 {
+			l.page.Text(x+4, baseline, 8.5, bold, color, truncate(value, widths[i]-8, bold))
+		} else {
+			l.page.TextRight(x+widths[i]-4, baseline, 8.5, bold, color, value)
+		}
+		x += widths[i]
+	}
+}
+
+// footers numera as páginas depois que todas foram criadas
+func (l *layout) footers(generatedAt time.Time) {
+	for i, page := range l.pages {
+		y := pdf.PageHeight - margin + 10
+		page.Text(margin, y, 8, false, colorMuted, "LightApp - gerado em "+generatedAt.Format("02/01/2006 15:04"))
+		page.TextRight(pdf.PageWidth-margin, y, 8, false, colorMuted, fmt.Sprintf("Página %d de %d", i+1, len(l.pages)))
+	}
+}
+
+func writeCover(l *layout, r NutritionReport) {
+	l.page.Rect(0, 0, pdf.PageWidth, 120, colorAccent)
+	l.page.Text(margin, 62, 26, true, colorWhite, "Relatório nutricional")
+	l.page.Text(margin, 90, 12, false, colorWhite,
+		fmt.Sprintf("%s a %s", r.From.Format("02/01/2006"), r.To.Format("02/01/2006")))
+	l.y = 150
+
+	l.heading("Perfil")
+	user := r.User
+	sex := "-"
+	switch user.Sex {
+	case models.SexMale:
+		sex = "Masculino"
+	case models.SexFemale:
+		sex = "Feminino"
+	}
+	goal, ok := goalLabels[user.Goal]
+	if !ok {
+		goal = "Manter peso"
+	}
+	activity := "-"
+	if preset, ok := models.FindActivityPreset(user.Activity); ok {
+		activity = fmt.Sprintf("%s (%s)", preset.Name, number(preset.Multiplier, 3))
+	}
+	imc := "-"
+	if r.IMC > 0 {
+		imc = fmt.Sprintf("%s (%s)", number(r.IMC, 1), r.IMCStatus)
+	}
+	profile := [][2]string{
+		{"Nome", user.Name},
+		{"Idade", fmt.Sprintf("%d anos", user.Age)},
+		{"Sexo", sex},
+		{"Altura", number(user.Height, 0) + " cm"},
+		{"Peso", number(user.Weight, 1) + " kg"},
+		{"IMC", imc},
+		{"Nível de atividade", activity},
+		{"Objetivo", goal},
+	}
+	for _, field := range profile {
+		l.page.Text(margin, l.y, 10, true, colorText, field[0])
+		l.page.Text(margin+140, l.y, 10, false, colorText, field[1])
+		l.y += 16
+	}
+
+	period := models.MergeSummaryBuckets(r.Days)
+	l.heading("Resumo do período")
+	l.paragraph(fmt.Sprintf("%d dias no período, %d com registro e %d sem registro. As médias consideram apenas os dias com registro.",
+		period.Days, period.LoggedDays, period.EmptyDays))
+	l.y += 4
+
+	header := []string{"", "Energia (kcal)", "Proteína (g)", "Carboidratos (g)", "Gordura (g)", "Fibra (g)"}
+	widths := []float64{115, 80, 80, 80, 80, 80}
+	average := period.Average
+	rows := [][]string{{"Média diária", number(average.Calories, 0), number(average.Protein, 1),
+		number(average.Carbs, 1), number(average.Fat, 1), number(average.Fiber, 1)}}
+	if t := r.Targets; t != nil {
+		rows = append(rows,
+			[]string{"Meta", number(t.Calories, 0), number(t.Protein, 1), number(t.Carbs, 1), number(t.Fat, 1), number(t.Fiber, 1)},
+			[]string{"Média / meta", percent(average.Calories, t.Calories), percent(average.Protein, t.Protein),
+				percent(average.Carbs, t.Carbs), percent(average.Fat, t.Fat), percent(average.Fiber, t.Fiber)},
+		)
+	}
+	l.table(widths, header, rows, nil)
+	if r.Targets == nil {
+		l.paragraph("Metas não calculadas: o perfil está incompleto.")
+	}
+}
+
+func writeCharts(l *layout, r NutritionReport) {
+	l.newPage()
+	l.heading("Tendências")
+
+	var target models.NutritionTargets
+	if r.Targets != nil {
+		target = *r.Targets
+	}
+	series := func(value func(models.Nutrients) float64) []float64 {
+		values := make([]float64, len(r.Days))
+		for i, day := range r.Days {
+			values[i] = value(day.Total)
+		}
+		return values
+	}
+
+	width := pdf.PageWidth - 2*margin
+	l.chart("Energia por dia (kcal)", width, 200, r.Days, []chartSeries{
+		{label: "Consumido", values: series(func(n models.Nutrients) float64 { return n.Calories }), color: colorAccent, target: target.Calories, bars: true},
+	})
+	l.chart("Macronutrientes por dia (g)", width, 220, r.Days, []chartSeries{
+		{label: "Proteína", values: series(func(n models.Nutrients) float64 { return n.Protein }), color: colorProtein, target: target.Protein},
+		{label: "Carboidratos", values: series(func(n models.Nutrients) float64 { return n.Carbs }), color: colorCarbs, target: target.Carbs},
+		{label: "Gordura", values: series(func(n models.Nutrients) float64 { return n.Fat }), color: colorFat, target: target.Fat},
+	})
+	if r.Targets != nil {
+		l.paragraph("Linhas tracejadas: metas diárias. Dias sem registro aparecem vazios e não entram nas médias.")
+	}
+}
+
+type chartSeries struct {
+	label  string
+	values []float64
+	color  pdf.Color
+	target float64
+	bars   bool
+}
+
+// chart desenha um gráfico de barras ou linhas com as metas tracejadas
+func (l *layout) chart(title string, width, height float64, days []models.SummaryBucket, series []chartSeries) {
+	l.ensure(height + 60)
+	l.page.Text(margin, l.y, 11, true, colorText, title)
+	l.y += 12
+
+	// Legenda
+	x := margin
+	for _, s := range series {
+		l.page.Rect(x, l.y-7, 8, 8, s.color)
+		l.page.Text(x+12, l.y, 8, false, colorMuted, s.label)
+		x += 22 + pdf.TextWidth(s.label, 8, false)
+	}
+	l.y += 10
+
+	top, left := l.y, margin+36
+	plotWidth, plotHeight := width-36, height-30
+	bottom := top + plotHeight
+
+	maxValue := 0.0
+	for _, s := range series {
+		maxValue = math.Max(maxValue, s.target)
+		for _, value := range s.values {
+			maxValue = math.Max(maxValue, value)
+		}
+	}
+	maxValue = niceCeiling(maxValue)
+
+	// Grade e eixo
+	for i := 0; i <= 4; i++ {
+		value := maxValue * float64(i) / 4
+		y := bottom - plotHeight*float64(i)/4
+		l.page.Line(left, y, left+plotWidth, y, 0.5, colorGrid)
+		l.page.TextRight(left-4, y+3, 7, false, colorMuted, number(value, 0))
+	}
+
+	n := len(days)
+	if n == 0 {
+		l.y = bottom + 30
+		return
+	}
+	step := plotWidth / float64(n)
+	valueY := func(value float64) float64 { return bottom - plotHeight*value/maxValue }
+
+	for _, s := range series {
+		if s.bars {
+			barWidth := math.Max(1, step*0.7)
+			for i, value := range s.values {
+				if value <= 0 {
+					continue
+				}
+				x := left + step*float64(i) + (step-barWidth)/2
+				l.page.Rect(x, valueY(value), barWidth, bottom-valueY(value), s.color)
+			}
+		} else {
+			// Dias sem registro interrompem a linha
+			var points [][2]float64
+			for i, value := range s.values {
+				if days[i].LoggedDays == 0 {
+					l.page.Polyline(points, 1.5, s.color)
+					points = nil
+					continue
+				}
+				points = append(points, [2]float64{left + step*(float64(i)+0.5), valueY(value)})
+			}
+			l.page.Polyline(points, 1.5, s.color)
+		}
+		if s.target > 0 {
+			color := s.color
+			if s.bars {
+				color = colorTarget
+			}
+			l.page.DashedLine(left, valueY(s.target), left+plotWidth, valueY(s.target), 1, color)
+		}
+	}
+
+	// Rótulos de data espaçados para não se sobreporem
+	every := int(math.Ceil(float64(n) * 32 / plotWidth))
+	for i := 0; i < n; i += max(1, every) {
+		date, _ := time.Parse(models.DateLayout, days[i].Start)
+		l.page.TextCenter(left+step*(float64(i)+0.5), bottom+12, 7, false, colorMuted, date.Format("02/01"))
+	}
+
+	l.y = bottom + 34
+}
+
+func writeDailyTable(l *layout, r NutritionReport) {
+	l.newPage()
+	l.heading("Registro diário")
+
+	header := []string{"Data", "Energia (kcal)", "% da meta", "Proteína (g)", "Carboidratos (g)", "Gordura (g)", "Fibra (g)"}
+	widths := []float64{85, 75, 65, 70, 75, 70, 75}
+	rows := make([][]string, len(r.Days))
+	for i, day := range r.Days {
+		date, _ := time.Parse(models.DateLayout, day.Start)
+		label := date.Format("02/01/2006") + " " + weekdays[date.Weekday()]
+		if day.LoggedDays == 0 {
+			rows[i] = []string{label, "sem registro", "", "", "", "", ""}
+			continue
+		}
+		target := "-"
+		if r.Targets != nil {
+			target = percent(day.Total.Calories, r.Targets.Calories)
+		}
+		total := day.Total
+		rows[i] = []string{label, number(total.Calories, 0), target, number(total.Protein, 1),
+			number(total.Carbs, 1), number(total.Fat, 1), number(total.Fiber, 1)}
+	}
+	l.table(widths, header, rows, func(row int) bool { return r.Days[row].LoggedDays == 0 })
+}
+
+func writeTopFoods(l *layout, r NutritionReport) {
+	l.heading("Alimentos que mais contribuíram com energia")
+	if len(r.TopFoods) == 0 {
+		l.paragraph("Nenhum alimento registrado no período.")
+		return
+	}
+
+	total := models.MergeSummaryBuckets(r.Days).Total.Calories
+	header := []string{"Alimento", "Registros", "Quantidade (g)", "Energia (kcal)", "% do total"}
+	widths := []float64{235, 55, 80, 75, 70}
+	rows := make([][]string, len(r.TopFoods))
+	for i, food := range r.TopFoods {
+		grams := "-"
+		if food.Grams > 0 {
+			grams = number(food.Grams, 0)
+		}
+		rows[i] = []string{food.Name, fmt.Sprint(food.Count), grams, number(food.Calories, 0), percent(food.Calories, total)}
+	}
+	l.table(widths, header, rows, nil)
+}
+
+func writeMicronutrients(l *layout, r NutritionReport) {
+	l.heading("Adequação de micronutrientes")
+	if len(r.Micronutrients) == 0 {
+		l.paragraph("Sem valores de referência para a idade e o sexo do perfil.")
+		return
+	}
+	l.paragraph("Média diária dos dias com registro comparada às DRIs (EAR/RDA/AI/UL), tabela de referência v" + r.ReferenceVersion + ".")
+	l.y += 4
+
+	header := []string{"Nutriente", "Média/dia", "EAR", "RDA / AI", "UL", "% RDA/AI", "Situação"}
+	widths := []float64{95, 65, 50, 60, 50, 55, 140}
+	rows := make([][]string, len(r.Micronutrients))
+	for i, a := range r.Micronutrients {
+		goal := optional(a.RDA)
+		if a.RDA == nil && a.AI != nil {
+			goal = optional(a.AI) + " (AI)"
+		}
+		rows[i] = []string{a.Label + " (" + a.Unit + ")", number(a.Intake, 1), optional(a.EAR), goal,
+			optional(a.UL), number(a.PercentOfGoal, 0) + "%", statusLabels[a.Status]}
+	}
+	l.table(widths, header, rows, func(row int) bool { return r.Micronutrients[row].Status == dri.StatusAdequate })
+}
+
+var weekdays = [...]string{"dom", "seg", "ter", "qua", "qui", "sex", "sáb"}
+
+// number formata com vírgula decimal e ponto de milhar
+func number(value float64, decimals int) string {
+	text := fmt.Sprintf("%.*f", decimals, value)
+	integer, fraction, _ := strings.Cut(text, ".")
+	negative := strings.HasPrefix(integer, "-")
+	integer = strings.TrimPrefix(integer, "-")
+	for i := len(integer) - 3; i > 0; i -= 3 {
+		integer = integer[:i] + "." + integer[i:]
+	}
+	if negative {
+		integer = "-" + integer
+	}
+	if fraction != "" {
+		return integer + "," + fraction
+	}
+	return integer
+}
+
+func percent(value, target float64) string {
+	if target <= 0 {
+		return "-"
+	}
+	return number(value/target*100, 0) + "%"
+}
+
+func optional(value *float64) string {
+	if value == nil {
+		return "-"
+	}
+	return number(*value, 1)
+}
+
+// niceCeiling arredonda o máximo do eixo para um valor redondo
+func niceCeiling(value float64) float64 {
+	if value <= 0 {
+		return 1
+	}
+	magnitude := math.Pow(10, math.Floor(math.Log10(value)))
+	for _, step := range []float64{1, 2, 2.5, 5, 10} {
+		if value <= step*magnitude {
+			return step * magnitude
+		}
+	}
+	return 10 * magnitude
+}
+
+// truncate corta o texto para caber na largura
+func truncate(text string, width float64, bold bool) string {
+	if pdf.TextWidth(text, 8.5, bold) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && pdf.TextWidth(string(runes)+"…", 8.5, bold) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
+
+func sum(values []float64) float64 {
+	total := 0.0
+	for _, value := range values {
+		total += value
+	}
+	return total
+}
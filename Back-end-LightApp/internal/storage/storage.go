@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+)
+
+// ErrNotFound indica que o objeto não existe no armazenamento
+var ErrNotFound = errors.New("objeto não encontrado")
+
+// Storage guarda arquivos enviados pelos usuários, identificados por chave
+// ("meals/12/abc.jpg"). As implementações não conhecem usuários nem permissões:
+// o controle de acesso fica nos handlers.
+type Storage interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Default é o armazenamento usado pela aplicação, definido em Init
+var Default Storage
+
+// Init escolhe o armazenamento a partir de STORAGE_BACKEND: "local" (padrão,
+// em STORAGE_DIR) ou "s3" (qualquer serviço compatível, como o MinIO)
+func Init() error {
+	switch os.Getenv("STORAGE_BACKEND") {
+	case "", "local":
+		dir := os.Getenv("STORAGE_DIR")
+		if dir == "" {
+			dir = "./uploads"
+		}
+		local, err := NewLocal(dir)
+		if err != nil {
+			return err
+		}
+		Default = local
+	case "s3":
+		s3, err := NewS3(S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+		})
+		if err != nil {
+			return err
+		}
+		Default = s3
+	default:
+		return errors.New("STORAGE_BACKEND desconhecido: " + os.Getenv("STORAGE_BACKEND"))
+	}
+	return nil
+}
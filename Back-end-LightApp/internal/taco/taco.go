@@ -0,0 +1,130 @@
+package taco
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/juliapinheiro42/LightApp/internal/models"
+)
+
+// Colunas da planilha da TACO 4ª edição
+const (
+	colID         = 0
+	colName       = 1
+	colMoisture   = 2
+	colCalories   = 3
+	colProtein    = 5
+	colFat        = 6
+	colCarbs      = 8
+	colFiber      = 9
+	colCalcium    = 11
+	colMagnesium  = 12
+	colManganese  = 14
+	colPhosphorus = 15
+	colIron       = 16
+	colSodium     = 17
+	colPotassium  = 18
+	colCopper     = 19
+	colZinc       = 20
+	colRAE        = 23 // Vitamina A em equivalentes de atividade de retinol
+	colThiamin    = 24
+	colRiboflavin = 25
+	colPyridoxine = 26
+	colNiacin     = 27
+	colVitaminC   = 28
+)
+
+// Parse lê a tabela TACO em CSV e retorna os alimentos com valores por 100 g.
+// O número do alimento na TACO é usado como ID.
+func Parse(r io.Reader) ([]models.Food, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var foods []models.Food
+	category := ""
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if len(record) <= colVitaminC {
+			continue
+		}
+
+		// Linhas de cabeçalho, categorias e notas não começam com o número do alimento
+		id, err := strconv.ParseUint(strings.TrimSpace(record[colID]), 10, 64)
+		if err != nil {
+			if isCategoryRow(record) {
+				category = strings.TrimSpace(record[colID])
+			}
+			continue
+		}
+
+		foods = append(foods, models.Food{
+			ID:       uint(id),
+			Name:     strings.TrimSpace(record[colName]),
+			Category: category,
+			Nutrients: models.Nutrients{
+				Calories: parseValue(record[colCalories]),
+				Protein:  parseValue(record[colProtein]),
+				Carbs:    parseValue(record[colCarbs]),
+				Fat:      parseValue(record[colFat]),
+				Fiber:    parseValue(record[colFiber]),
+				Water:    parseValue(record[colMoisture]),
+				Micronutrients: models.Micronutrients{
+					Calcium:    parseValue(record[colCalcium]),
+					Magnesium:  parseValue(record[colMagnesium]),
+					Manganese:  parseValue(record[colManganese]),
+					Phosphorus: parseValue(record[colPhosphorus]),
+					Iron:       parseValue(record[colIron]),
+					Sodium:     parseValue(record[colSodium]),
+					Potassium:  parseValue(record[colPotassium]),
+					Copper:     parseValue(record[colCopper]),
+					Zinc:       parseValue(record[colZinc]),
+					VitaminA:   parseValue(record[colRAE]),
+					Thiamin:    parseValue(record[colThiamin]),
+					Riboflavin: parseValue(record[colRiboflavin]),
+					VitaminB6:  parseValue(record[colPyridoxine]),
+					Niacin:     parseValue(record[colNiacin]),
+					VitaminC:   parseValue(record[colVitaminC]),
+				},
+			},
+		})
+	}
+
+	if len(foods) == 0 {
+		return nil, errors.New("nenhum alimento encontrado no arquivo da TACO")
+	}
+	return foods, nil
+}
+
+// isCategoryRow identifica as linhas que abrem um grupo ("Cereais e derivados"):
+// apenas a primeira coluna preenchida
+func isCategoryRow(record []string) bool {
+	if strings.TrimSpace(record[colID]) == "" {
+		return false
+	}
+	for _, field := range record[1:] {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// parseValue converte um valor da TACO; "NA", "Tr" (traços) e "*" viram zero
+func parseValue(raw string) float64 {
+	value, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(raw), ",", "."), 64)
+	if err != nil {
+		return 0
+	}
+	return value
+}
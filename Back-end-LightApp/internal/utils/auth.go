@@ -71,7 +71,7 @@ func ValidateRefreshToken(tokenString string) (string, error) {
 }
 
 func RevokeToken(token string) error {
-	revokedToken := models.RevokedToken{Token: token}
+	revokedToken := models.RevokedToken{Token: token, UserID: TokenUserID(token)}
 	result := database.DB.Create(&revokedToken)
 	return result.Error
 }
@@ -81,3 +81,15 @@ func IsTokenRevoked(token string) bool {
 	result := database.DB.Where("token = ?", token).First(&revoked)
 	return result.RowsAffected > 0
 }
+
+// TokenUserID lê o user_id de um token sem validar assinatura nem validade.
+// Serve apenas para associar tokens já recebidos ao usuário; retorna zero se
+// o token não tiver o campo.
+func TokenUserID(tokenString string) uint {
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(tokenString, claims); err != nil {
+		return 0
+	}
+	userID, _ := claims["user_id"].(float64)
+	return uint(userID)
+}
@@ -4,13 +4,16 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	_ "time/tzdata" // Fusos horários dos usuários mesmo sem tzdata no sistema
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-resty/resty/v2"
 	"github.com/juliapinheiro42/LightApp/database"
+	"github.com/juliapinheiro42/LightApp/internal/account"
 	"github.com/juliapinheiro42/LightApp/internal/handlers"
 	"github.com/juliapinheiro42/LightApp/internal/middleware"
 	"github.com/juliapinheiro42/LightApp/internal/models"
+	"github.com/juliapinheiro42/LightApp/internal/storage"
 )
 
 const edamamURL = "https://api.edamam.com/api/food-database/v2/parser"
@@ -91,6 +94,90 @@ func main() {
 	if err := models.MigrateFood(database.DB); err != nil {
 		panic("Falha ao migrar tabela de alimentos")
 	}
+	if err := models.MigrateUser(database.DB); err != nil {
+		panic("Falha ao migrar tabela de usuários")
+	}
+	if err := models.MigrateMeal(database.DB); err != nil {
+		panic("Falha ao migrar tabelas de refeições")
+	}
+	if err := models.MigrateMealPhoto(database.DB); err != nil {
+		panic("Falha ao migrar tabela de fotos de refeições")
+	}
+	if err := models.MigrateMealPlan(database.DB); err != nil {
+		panic("Falha ao migrar tabelas de planos alimentares")
+	}
+	if err := models.MigrateShoppingList(database.DB); err != nil {
+		panic("Falha ao migrar tabelas de listas de compras")
+	}
+	if err := models.MigrateRecipe(database.DB); err != nil {
+		panic("Falha ao migrar tabelas de receitas")
+	}
+	if err := models.MigrateNutritionistAccess(database.DB); err != nil {
+		panic("Falha ao migrar tabela de acessos de nutricionistas")
+	}
+	if err := models.MigrateFasting(database.DB); err != nil {
+		panic("Falha ao migrar tabela de jejum")
+	}
+	if err := models.MigrateHydration(database.DB); err != nil {
+		panic("Falha ao migrar tabela de hidratação")
+	}
+	if err := models.MigrateAuditLog(database.DB); err != nil {
+		panic("Falha ao migrar tabela de auditoria")
+	}
+	if err := models.MigrateDailySummary(database.DB); err != nil {
+		panic("Falha ao migrar tabela de totais diários")
+	}
+	if err := models.MigrateWeight(database.DB); err != nil {
+		panic("Falha ao migrar tabela de pesagens")
+	}
+	if err := models.MigrateBodyMeasurement(database.DB); err != nil {
+		panic("Falha ao migrar tabela de medidas corporais")
+	}
+
+	// Itens registrados antes do snapshot de nutrientes recebem os valores atuais do catálogo
+	if _, err := models.RecalculateMealItemNutrients(database.DB, models.MealItemRecalcFilter{OnlyMissing: true}); err != nil {
+		panic("Falha ao preencher nutrientes dos itens de refeição")
+	}
+
+	// Na primeira execução com a tabela de totais diários, ela é preenchida a partir dos itens
+	var summaries int64
+	database.DB.Model(&models.DailySummary{}).Limit(1).Count(&summaries)
+	if summaries == 0 {
+		if _, err := models.RebuildDailySummaries(database.DB, nil, "", ""); err != nil {
+			panic("Falha ao preencher totais diários")
+		}
+	}
+
+	// Itens com snapshot anterior a fibras, água e micronutrientes recebem essas colunas
+	if userIDs, err := models.FillLateSnapshotColumns(database.DB); err != nil {
+		panic("Falha ao preencher fibras, água e micronutrientes dos itens de refeição")
+	} else if len(userIDs) > 0 {
+		if _, err := models.RebuildDailySummaries(database.DB, userIDs, "", ""); err != nil {
+			panic("Falha ao refazer totais diários")
+		}
+	}
+
+	// Na primeira execução com o histórico de peso, o peso do perfil vira a primeira pesagem
+	var weighings int64
+	database.DB.Model(&models.WeightEntry{}).Limit(1).Count(&weighings)
+	if weighings == 0 {
+		if _, err := models.BackfillWeightEntries(database.DB); err != nil {
+			panic("Falha ao preencher histórico de pesagens")
+		}
+	}
+
+	// Perfis anteriores às equações selecionáveis: sexo a partir do gênero e nível de atividade pelo fator
+	if err := models.BackfillEnergyProfile(database.DB); err != nil {
+		panic("Falha ao preencher sexo e nível de atividade dos perfis")
+	}
+
+	// Armazenamento das fotos de refeições
+	if err := storage.Init(); err != nil {
+		panic("Falha ao configurar armazenamento de arquivos: " + err.Error())
+	}
+
+	// Remoção definitiva das contas cujo prazo de exclusão terminou
+	account.StartPurger(database.DB, storage.Default)
 
 	r := gin.Default()
 
@@ -142,6 +229,7 @@ func main() {
 		api.POST("/refresh", handlers.RefreshToken)
 		api.POST("/logout", handlers.Logout)
 		api.POST("/upload", uploadImage)
+		api.GET("/photos/:photo_id/:variant", handlers.ServeMealPhoto) // URL assinada, sem token
 
 		protected := api.Group("/")
 		protected.Use(middleware.AuthMiddleware())
@@ -152,21 +240,121 @@ func main() {
 
 		// Rotas para refeições
 		protected.POST("/meals", handlers.CreateMeal)
+		protected.GET("/meals", handlers.ListMeals)
+		protected.PATCH("/meals/:meal_id", handlers.UpdateMealDetails)
 		protected.POST("/meals/items", handlers.AddMealItem)
+		protected.POST("/meals/parse", handlers.ParseMealText)
 		protected.GET("/meals/:meal_id/summary", handlers.GetMealSummary)
 
+		// Rotas para fotos das refeições
+		protected.POST("/meals/:meal_id/photo", handlers.UploadMealPhoto)
+		protected.GET("/meals/:meal_id/photo", handlers.GetMealPhoto)
+		protected.DELETE("/meals/:meal_id/photo", handlers.DeleteMealPhoto)
+
 		// Rotas para cálculo do usuário
 		protected.GET("/imc", handlers.CalculateIMC)
 		protected.GET("/calories", handlers.CalculateCalories)
+		protected.GET("/calories/equations", handlers.GetEnergyOptions)
+		protected.GET("/calories/adaptive", handlers.GetAdaptiveTDEE)
 		protected.PUT("/user", handlers.UpdateUser)
 
+		// Rotas para metas de calorias e macronutrientes
+		protected.GET("/user/targets", handlers.GetTargets)
+		protected.PUT("/user/targets", handlers.UpdateTargets)
+		protected.DELETE("/user/targets", handlers.ResetTargets)
+
 		// Rota para buscar alimentos do TACO
 		protected.GET("/foods/taco/:query", handlers.GetFood)
 		protected.GET("/foods/taco/id/:id", handlers.GetFoodByID)
 
+		// Rotas para alimentos recentes e frequentes do usuário
+		protected.GET("/foods/recent", handlers.GetRecentFoods)
+		protected.GET("/foods/frequent", handlers.GetFrequentFoods)
+
 		//Rotas para sumário diário de calorias
 		protected.GET("/user/daily-summary", handlers.GetDailySummary)
 
+		// Rota para resumo nutricional por período
+		protected.GET("/summary", handlers.GetNutritionSummary)
+
+		// Rotas para relatórios
+		protected.GET("/reports/micronutrients", handlers.GetMicronutrientReport)
+		protected.GET("/reports/pdf", handlers.GetNutritionReportPDF)
+
+		// Rota para exportação do diário
+		protected.GET("/export/diary", handlers.ExportDiary)
+
+		// Rotas para planos alimentares
+		protected.POST("/meal-plans", handlers.CreateMealPlan)
+		protected.GET("/meal-plans", handlers.ListMealPlans)
+		protected.GET("/meal-plans/:id", handlers.GetMealPlan)
+		protected.DELETE("/meal-plans/:id", handlers.DeleteMealPlan)
+		protected.GET("/meal-plans/:id/day", handlers.GetMealPlanDay)
+		protected.POST("/meal-plans/:id/meals/:planned_meal_id/eaten", handlers.MarkPlannedMealEaten)
+
+		// Rotas para o acesso de nutricionistas aos planos alimentares
+		protected.POST("/nutritionists", handlers.GrantNutritionistAccess)
+		protected.GET("/nutritionists", handlers.ListNutritionists)
+		protected.DELETE("/nutritionists/:id", handlers.RevokeNutritionistAccess)
+		protected.GET("/clients", handlers.ListClients)
+		protected.POST("/clients/:client_id/meal-plans", handlers.CreateMealPlan)
+		protected.GET("/clients/:client_id/meal-plans", handlers.ListMealPlans)
+		protected.GET("/clients/:client_id/meal-plans/:id", handlers.GetMealPlan)
+		protected.DELETE("/clients/:client_id/meal-plans/:id", handlers.DeleteMealPlan)
+		protected.GET("/clients/:client_id/meal-plans/:id/day", handlers.GetMealPlanDay)
+
+		// Rotas para listas de compras
+		protected.POST("/recipes", handlers.CreateRecipe)
+		protected.GET("/recipes", handlers.ListRecipes)
+		protected.GET("/recipes/:id", handlers.GetRecipe)
+		protected.PUT("/recipes/:id", handlers.UpdateRecipe)
+		protected.DELETE("/recipes/:id", handlers.DeleteRecipe)
+
+		protected.POST("/shopping-lists", handlers.CreateShoppingList)
+		protected.GET("/shopping-lists", handlers.ListShoppingLists)
+		protected.GET("/shopping-lists/:id", handlers.GetShoppingList)
+		protected.GET("/shopping-lists/:id/export", handlers.ExportShoppingList)
+		protected.PATCH("/shopping-lists/:id/items/:item_id", handlers.CheckShoppingListItem)
+		protected.DELETE("/shopping-lists/:id", handlers.DeleteShoppingList)
+
+		// Rotas para dados pessoais e exclusão da conta (LGPD)
+		protected.GET("/me/export", handlers.ExportMyData)
+		protected.POST("/me/import", handlers.ImportMyData)
+		protected.DELETE("/me", handlers.DeleteAccount)
+
+		// Rota para importar diários de outros aplicativos
+		protected.POST("/import/csv", handlers.ImportCSV)
+
+		// Rotas para o histórico de peso
+		protected.POST("/weight", handlers.LogWeight)
+		protected.GET("/weight", handlers.GetWeightHistory)
+		protected.PUT("/weight/:id", handlers.UpdateWeight)
+		protected.DELETE("/weight/:id", handlers.DeleteWeight)
+
+		// Rotas para medidas corporais e composição corporal
+		protected.POST("/measurements", handlers.LogMeasurement)
+		protected.GET("/measurements", handlers.GetMeasurements)
+		protected.PUT("/measurements/:id", handlers.UpdateMeasurement)
+		protected.DELETE("/measurements/:id", handlers.DeleteMeasurement)
+
+		// Rotas para jejum intermitente
+		protected.POST("/fasting/start", handlers.StartFast)
+		protected.POST("/fasting/end", handlers.EndFast)
+		protected.GET("/fasting/current", handlers.GetCurrentFast)
+		protected.GET("/fasting/history", handlers.GetFastingHistory)
+
+		// Rotas para hidratação
+		protected.POST("/hydration", handlers.LogHydration)
+		protected.PUT("/hydration/:id", handlers.UpdateHydration)
+		protected.DELETE("/hydration/:id", handlers.DeleteHydration)
+		protected.GET("/hydration", handlers.GetHydrationSummary)
+		protected.PUT("/hydration/goal", handlers.UpdateHydrationGoal)
+
+		// Rotas administrativas
+		admin := protected.Group("/admin")
+		admin.Use(middleware.AdminMiddleware())
+		admin.POST("/meal-items/recalculate", handlers.RecalculateMealItems)
+
 	}
 
 	r.Run(":8081")
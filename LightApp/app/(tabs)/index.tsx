@@ -11,12 +11,27 @@ type Meal = {
   id: string; // Adicione um ID para cada refeição
 };
 
+// Tipagem do progresso em uma meta
+type TargetProgress = {
+  target: number;
+  consumed: number;
+  remaining: number;
+  percent: number;
+};
+
 // Tipagem para o resumo diário
 type DailySummary = {
   calories: number;
   proteins: number;
   carbs: number;
   fats: number;
+  progress: {
+    calories: TargetProgress;
+    protein: TargetProgress;
+    carbs: TargetProgress;
+    fat: TargetProgress;
+    fiber: TargetProgress;
+  } | null;
 };
 
 const HomeScreen = () => {
@@ -27,6 +42,7 @@ const HomeScreen = () => {
     proteins: 0,
     carbs: 0,
     fats: 0,
+    progress: null,
   });
 
   const { token } = useAuth(); // Use o hook personalizado
@@ -64,12 +80,15 @@ const HomeScreen = () => {
         proteins: data.proteins || 0,
         carbs: data.carbs || 0,
         fats: data.fats || 0,
+        progress: data.progress || null,
       });
     } catch (error) {
       console.error("Erro ao buscar resumo diário:", error);
     }
   };
 
+  const { progress } = dailySummary;
+
   return (
     <View style={styles.container}>
       {/* Header */}
@@ -95,11 +114,35 @@ const HomeScreen = () => {
           <Text style={styles.summaryTitle}>DIÁRIO ALIMENTAR</Text>
           <View style={styles.summaryContent}>
             <View>
-              <Text style={styles.summaryText}>Gorduras: {dailySummary.fats.toFixed(1)}g</Text>
-              <Text style={styles.summaryText}>Carboidratos: {dailySummary.carbs.toFixed(1)}g</Text>
-              <Text style={styles.summaryText}>Proteínas: {dailySummary.proteins.toFixed(1)}g</Text>
+              <Text style={styles.summaryText}>
+                Gorduras: {dailySummary.fats.toFixed(1)}g
+                {progress && ` / ${progress.fat.target.toFixed(0)}g`}
+              </Text>
+              <Text style={styles.summaryText}>
+                Carboidratos: {dailySummary.carbs.toFixed(1)}g
+                {progress && ` / ${progress.carbs.target.toFixed(0)}g`}
+              </Text>
+              <Text style={styles.summaryText}>
+                Proteínas: {dailySummary.proteins.toFixed(1)}g
+                {progress && ` / ${progress.protein.target.toFixed(0)}g`}
+              </Text>
+              {progress && (
+                <Text style={styles.summaryText}>
+                  Fibras: {progress.fiber.consumed.toFixed(1)}g / {progress.fiber.target.toFixed(0)}g
+                </Text>
+              )}
+            </View>
+            <View>
+              <Text style={styles.caloriesText}>Calorias: {dailySummary.calories.toFixed(0)}</Text>
+              {progress && (
+                <Text style={styles.summaryText}>
+                  {progress.calories.remaining >= 0
+                    ? `Restam ${progress.calories.remaining.toFixed(0)} kcal`
+                    : `${Math.abs(progress.calories.remaining).toFixed(0)} kcal acima da meta`}
+                  {` (${progress.calories.percent.toFixed(0)}%)`}
+                </Text>
+              )}
             </View>
-            <Text style={styles.caloriesText}>Calorias: {dailySummary.calories.toFixed(0)}</Text>
           </View>
         </View>
 
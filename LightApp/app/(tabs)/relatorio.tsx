@@ -1,27 +1,79 @@
-import React, { useState } from "react";
-import { View, Text, TouchableOpacity, StyleSheet, ScrollView } from "react-native";
+import React, { useContext, useEffect, useState } from "react";
+import { View, Text, TouchableOpacity, StyleSheet, ActivityIndicator } from "react-native";
+import { AuthContext } from "../Utils/AuthContext";
+
+type SummaryBucket = {
+  start: string;
+  end: string;
+  days: number;
+  logged_days: number;
+  empty_days: number;
+  total: { calories: number; protein: number; carbs: number; fat: number };
+  average: { calories: number; protein: number; carbs: number; fat: number };
+};
+
+const weekdayNames = ["Dom", "Seg", "Ter", "Qua", "Qui", "Sex", "Sáb"];
 
 export default function WeeklyReportScreen() {
+  const { token } = useContext(AuthContext);
   const [showMacros, setShowMacros] = useState(false); // Estado para alternar entre calorias e macros
+  const [days, setDays] = useState<SummaryBucket[]>([]);
+  const [period, setPeriod] = useState<SummaryBucket | null>(null);
+  const [loading, setLoading] = useState(true);
+  const [error, setError] = useState<string | null>(null);
+
+  // Buscar o resumo dos últimos 7 dias, dia a dia
+  useEffect(() => {
+    const fetchSummary = async () => {
+      if (!token) return;
+      setLoading(true);
+      try {
+        const res = await fetch("http://10.0.2.2:8081/api/summary?group=day", {
+          headers: { Authorization: `Bearer ${token}` },
+        });
+        const data = await res.json();
+        if (!res.ok) {
+          setError(data.error || "Erro ao carregar relatório");
+          return;
+        }
+        setDays(data.buckets);
+        setPeriod(data.period);
+        setError(null);
+      } catch (err) {
+        console.error("Erro ao buscar resumo:", err);
+        setError("Erro ao carregar relatório");
+      } finally {
+        setLoading(false);
+      }
+    };
+
+    fetchSummary();
+  }, [token]);
 
-  // Dados fictícios de calorias e macros consumidas na semana
-  const weeklyData = [
-    { day: "Segunda", calories: 1800, protein: 120, carbs: 200, fat: 60 },
-    { day: "Terça", calories: 2200, protein: 150, carbs: 250, fat: 80 },
-    { day: "Quarta", calories: 1900, protein: 130, carbs: 210, fat: 70 },
-    { day: "Quinta", calories: 2100, protein: 140, carbs: 230, fat: 75 },
-    { day: "Sexta", calories: 2000, protein: 135, carbs: 220, fat: 72 },
-    { day: "Sábado", calories: 2400, protein: 160, carbs: 270, fat: 90 },
-    { day: "Domingo", calories: 2300, protein: 155, carbs: 260, fat: 85 },
-  ];
+  if (loading) {
+    return (
+      <View style={styles.container}>
+        <ActivityIndicator color="#10B981" />
+      </View>
+    );
+  }
+
+  if (error || !period) {
+    return (
+      <View style={styles.container}>
+        <Text style={styles.title}>Relatório Semanal</Text>
+        <Text style={styles.summarySubtitle}>{error}</Text>
+      </View>
+    );
+  }
 
-  // Calcular totais e médias
-  const totalCalories = weeklyData.reduce((sum, day) => sum + day.calories, 0);
-  const averageCalories = (totalCalories / weeklyData.length).toFixed(0);
+  // Totais e médias vêm calculados da API; a média considera só os dias com registro
+  const totalCalories = period.total.calories.toFixed(0);
+  const averageCalories = period.average.calories.toFixed(0);
 
-  const totalProtein = weeklyData.reduce((sum, day) => sum + day.protein, 0);
-  const totalCarbs = weeklyData.reduce((sum, day) => sum + day.carbs, 0);
-  const totalFat = weeklyData.reduce((sum, day) => sum + day.fat, 0);
+  const totalProtein = period.total.protein.toFixed(0);
+  const totalCarbs = period.total.carbs.toFixed(0);
+  const totalFat = period.total.fat.toFixed(0);
 
   return (
     <View style={styles.container}>
@@ -42,17 +94,22 @@ export default function WeeklyReportScreen() {
             <Text style={styles.summaryTitle}>Total de Calorias na Semana</Text>
             <Text style={styles.summaryValue}>{totalCalories} kcal</Text>
             <Text style={styles.summarySubtitle}>Média diária: {averageCalories} kcal</Text>
+            <Text style={styles.summarySubtitle}>
+              {period.logged_days} de {period.days} dias com registro
+            </Text>
           </>
         )}
       </View>
 
       {/* Gráfico de Barras (simulado) */}
       <View style={styles.chartContainer}>
-        {weeklyData.map((day, index) => {
-          // Calcular porcentagens de cada macro
-          const proteinPercentage = (day.protein * 4 / day.calories) * 100; // 1g de proteína = 4 kcal
-          const carbsPercentage = (day.carbs * 4 / day.calories) * 100; // 1g de carboidrato = 4 kcal
-          const fatPercentage = (day.fat * 9 / day.calories) * 100; // 1g de gordura = 9 kcal
+        {days.map((day, index) => {
+          // Calcular porcentagens de cada macro (dias sem registro ficam vazios)
+          const { calories, protein, carbs, fat } = day.total;
+          const proteinPercentage = calories > 0 ? (protein * 4 / calories) * 100 : 0; // 1g de proteína = 4 kcal
+          const carbsPercentage = calories > 0 ? (carbs * 4 / calories) * 100 : 0; // 1g de carboidrato = 4 kcal
+          const fatPercentage = calories > 0 ? (fat * 9 / calories) * 100 : 0; // 1g de gordura = 9 kcal
+          const weekday = weekdayNames[new Date(`${day.start}T12:00:00`).getDay()];
 
           return (
             <View key={index} style={styles.barContainer}>
@@ -88,7 +145,7 @@ export default function WeeklyReportScreen() {
                   ]}
                 />
               </View>
-              <Text style={styles.barLabel}>{day.day}</Text>
+              <Text style={styles.barLabel}>{weekday}</Text>
             </View>
           );
         })}